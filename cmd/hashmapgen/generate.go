@@ -0,0 +1,751 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// config holds the parameters of a single generated Map.
+type config struct {
+	// Package is the name of the package the code is generated into
+	Package string
+	// Name is the name of the generated Map type
+	Name string
+	// Key and Value are the resolved key and value types
+	Key, Value *typeInfo
+}
+
+// generator writes the source of a typed Map and of its tests.
+type generator struct {
+	config
+	// prefix is prepended to every unexported helper, so that several Maps can
+	// be generated into the same package
+	prefix string
+	// keyTypes are the types whose hash function must be generated
+	keyTypes []*typeInfo
+	// allTypes are the types whose equality and sample functions must be generated
+	allTypes []*typeInfo
+}
+
+// newGenerator collects the types reachable from the key and the value of c.
+func newGenerator(c config) *generator {
+	g := &generator{config: c, prefix: strings.ToLower(c.Name[:1]) + c.Name[1:]}
+
+	ids := make(map[string]string)
+	seen := make(map[string]bool)
+	g.keyTypes = collect(c.Key, seen, ids, nil)
+	g.allTypes = collect(c.Value, seen, ids, append([]*typeInfo(nil), g.keyTypes...))
+
+	return g
+}
+
+// collect appends to list the non-basic types reachable from t that are not in seen.
+//
+// Types are identified by their expression; if two different types share the same
+// id, the later one is renamed so that their helpers do not clash.
+func collect(t *typeInfo, seen map[string]bool, ids map[string]string, list []*typeInfo) []*typeInfo {
+	if t.kind == kindBasic || seen[t.expr] {
+		return list
+	}
+	seen[t.expr] = true
+
+	for n := 2; ids[t.id] != "" && ids[t.id] != t.expr; n++ {
+		t.id = strings.TrimRight(t.id, "0123456789") + strconv.Itoa(n)
+	}
+	ids[t.id] = t.expr
+	list = append(list, t)
+
+	switch t.kind {
+	case kindArray, kindSlice:
+		list = collect(t.elem, seen, ids, list)
+	case kindStruct:
+		for _, f := range t.fields {
+			list = collect(f.typ, seen, ids, list)
+		}
+	}
+
+	return list
+}
+
+// Source returns the formatted source of the Map.
+func (g *generator) Source() ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := mapTemplate.Execute(&buf, g); err != nil {
+		return nil, err
+	}
+
+	for _, t := range g.keyTypes {
+		g.writeHash(&buf, t)
+	}
+	for _, t := range g.allTypes {
+		g.writeEqual(&buf, t)
+	}
+
+	return formatSource(buf.Bytes())
+}
+
+// TestSource returns the formatted source of the tests of the Map.
+func (g *generator) TestSource() ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := testTemplate.Execute(&buf, g); err != nil {
+		return nil, err
+	}
+
+	for _, t := range g.allTypes {
+		g.writeSample(&buf, t)
+	}
+
+	return formatSource(buf.Bytes())
+}
+
+// UsesStrconv reports whether the sample functions need the strconv package.
+func (g *generator) UsesStrconv() bool {
+	for _, t := range append([]*typeInfo{g.Key, g.Value}, g.allTypes...) {
+		if t.kind == kindBasic && t.basic == "string" {
+			return true
+		}
+		for _, f := range t.fields {
+			if f.typ.kind == kindBasic && f.typ.basic == "string" {
+				return true
+			}
+		}
+		if t.elem != nil && t.elem.kind == kindBasic && t.elem.basic == "string" {
+			return true
+		}
+	}
+	return false
+}
+
+// Prefix returns the prefix of the unexported helpers.
+func (g *generator) Prefix() string { return g.prefix }
+
+// KeyType returns the key type as written in the generated code.
+func (g *generator) KeyType() string { return g.Key.expr }
+
+// ValueType returns the value type as written in the generated code.
+func (g *generator) ValueType() string { return g.Value.expr }
+
+// HashKey returns the statement that mixes key into the running hash h.
+func (g *generator) HashKey() string { return g.hashStmt(g.Key, "key") }
+
+// EqualKeys returns the expression comparing the keys a and b.
+func (g *generator) EqualKeys() string { return g.equalExpr(g.Key, "a", "b") }
+
+// EqualValues returns the expression comparing the values a and b.
+func (g *generator) EqualValues() string { return g.equalExpr(g.Value, "a", "b") }
+
+// SampleKey returns the expression building the i-th sample key.
+func (g *generator) SampleKey() string { return g.sampleExpr(g.Key, "i", "0") }
+
+// SampleValue returns the expression building the i-th sample value.
+func (g *generator) SampleValue() string { return g.sampleExpr(g.Value, "i", "0") }
+
+// hashStmt returns the statement that mixes v, of type t, into the running hash h.
+func (g *generator) hashStmt(t *typeInfo, v string) string {
+	if t.kind != kindBasic {
+		return fmt.Sprintf("h = %sHash%s(h, %s)", g.prefix, t.id, v)
+	}
+
+	switch t.basic {
+	case "bool":
+		return fmt.Sprintf("h = %sHashBool(h, bool(%s))", g.prefix, v)
+	case "string":
+		return fmt.Sprintf("h = %sHashString(h, string(%s))", g.prefix, v)
+	case "float32", "float64":
+		return fmt.Sprintf("h = %sHashFloat(h, float64(%s))", g.prefix, v)
+	case "complex64", "complex128":
+		return fmt.Sprintf("h = %[1]sHashFloat(%[1]sHashFloat(h, float64(real(%[2]s))), float64(imag(%[2]s)))", g.prefix, v)
+	}
+
+	return fmt.Sprintf("h = %sHashUint64(h, uint64(%s))", g.prefix, v)
+}
+
+// equalExpr returns the expression comparing a and b, of type t.
+func (g *generator) equalExpr(t *typeInfo, a, b string) string {
+	if t.kind == kindBasic {
+		return a + " == " + b
+	}
+	return fmt.Sprintf("%sEqual%s(%s, %s)", g.prefix, t.id, a, b)
+}
+
+// sampleExpr returns the expression building the i-th sample of type t.
+func (g *generator) sampleExpr(t *typeInfo, i, depth string) string {
+	if t.kind != kindBasic {
+		return fmt.Sprintf("%sSample%s(%s, %s)", g.prefix, t.id, i, depth)
+	}
+
+	switch t.basic {
+	case "bool":
+		return fmt.Sprintf("%s(%s%%2 == 1)", t.expr, i)
+	case "string":
+		return fmt.Sprintf("%s(strconv.Itoa(%s))", t.expr, i)
+	case "complex64", "complex128":
+		return fmt.Sprintf("%s(complex(float64(%s), 0))", t.expr, i)
+	}
+
+	return fmt.Sprintf("%s(%s)", t.expr, i)
+}
+
+// writeHash writes the hash function of t.
+func (g *generator) writeHash(buf *bytes.Buffer, t *typeInfo) {
+	fmt.Fprintf(buf, "\n// %sHash%s mixes a %s into the running hash h.\n", g.prefix, t.id, t.expr)
+	fmt.Fprintf(buf, "func %sHash%s(h uint32, v %s) uint32 {\n", g.prefix, t.id, t.expr)
+
+	switch t.kind {
+	case kindStruct:
+		for _, f := range t.fields {
+			fmt.Fprintf(buf, "%s\n", g.hashStmt(f.typ, "v."+f.name))
+		}
+	case kindArray, kindSlice:
+		if t.kind == kindSlice {
+			fmt.Fprintf(buf, "h = %sHashUint64(h, uint64(len(v)))\n", g.prefix)
+		}
+		fmt.Fprintf(buf, "for i := range v {\n%s\n}\n", g.hashStmt(t.elem, "v[i]"))
+	}
+
+	fmt.Fprintf(buf, "return h\n}\n")
+}
+
+// writeEqual writes the equality function of t.
+func (g *generator) writeEqual(buf *bytes.Buffer, t *typeInfo) {
+	fmt.Fprintf(buf, "\n// %sEqual%s returns true if a and b are equal.\n", g.prefix, t.id)
+	fmt.Fprintf(buf, "func %sEqual%s(a, b %s) bool {\n", g.prefix, t.id, t.expr)
+
+	switch t.kind {
+	case kindStruct:
+		if len(t.fields) == 0 {
+			fmt.Fprintf(buf, "return true\n}\n")
+			return
+		}
+		exprs := make([]string, 0, len(t.fields))
+		for _, f := range t.fields {
+			exprs = append(exprs, g.equalExpr(f.typ, "a."+f.name, "b."+f.name))
+		}
+		fmt.Fprintf(buf, "return %s\n}\n", strings.Join(exprs, " &&\n"))
+		return
+	case kindSlice:
+		fmt.Fprintf(buf, "if len(a) != len(b) || (a == nil) != (b == nil) {\nreturn false\n}\n")
+	}
+
+	fmt.Fprintf(buf, "for i := range a {\nif !(%s) {\nreturn false\n}\n}\n", g.equalExpr(t.elem, "a[i]", "b[i]"))
+	fmt.Fprintf(buf, "return true\n}\n")
+}
+
+// writeSample writes the function building the samples of t used by the tests.
+//
+// Slices hold a single element up to a small depth, so that recursive types terminate.
+func (g *generator) writeSample(buf *bytes.Buffer, t *typeInfo) {
+	fmt.Fprintf(buf, "\n// %sSample%s returns the i-th sample %s.\n", g.prefix, t.id, t.expr)
+	fmt.Fprintf(buf, "func %sSample%s(i, depth int) %s {\n", g.prefix, t.id, t.expr)
+
+	switch t.kind {
+	case kindStruct:
+		fmt.Fprintf(buf, "return %s{\n", t.expr)
+		for _, f := range t.fields {
+			fmt.Fprintf(buf, "%s: %s,\n", f.name, g.sampleExpr(f.typ, "i", "depth+1"))
+		}
+		fmt.Fprintf(buf, "}\n}\n")
+	case kindArray:
+		fmt.Fprintf(buf, "var v %s\nfor j := range v {\nv[j] = %s\n}\nreturn v\n}\n", t.expr, g.sampleExpr(t.elem, "i+j", "depth+1"))
+	case kindSlice:
+		fmt.Fprintf(buf, "if depth > 2 {\nreturn nil\n}\nreturn %s{%s}\n}\n", t.expr, g.sampleExpr(t.elem, "i", "depth+1"))
+	}
+}
+
+// formatSource formats src, reporting it along with the error if it does not parse.
+func formatSource(src []byte) ([]byte, error) {
+	out, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("%w\n%s", err, src)
+	}
+	return out, nil
+}
+
+var mapTemplate = template.Must(template.New("map").Parse(`// Code generated by hashmapgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"fmt"
+	"iter"
+	"math"
+)
+
+// {{.Name}}Entry is an item in the hash table of a {{.Name}}.
+type {{.Name}}Entry struct {
+	Key   {{.KeyType}}
+	Value {{.ValueType}}
+
+	// Next is a pointer to the Next item in the chain
+	Next *{{.Name}}Entry
+}
+
+// {{.Name}} is a Map from {{.KeyType}} to {{.ValueType}}.
+// It behaves like hashmap.Map, but hashes and compares keys with generated code
+// instead of encoding/json and reflect.
+type {{.Name}} struct {
+	// size is the number of slots in the Map
+	size uint32
+	// data is a slice of pointers to the chains of entries
+	data []*{{.Name}}Entry
+
+	// Threshold is the maximum load factor before resizing the hash table.
+	Threshold float32
+}
+
+// New{{.Name}} returns a new {{.Name}} with the given size and threshold.
+func New{{.Name}}(size uint32, threshold float32) *{{.Name}} {
+	return &{{.Name}}{
+		size:      size,
+		data:      make([]*{{.Name}}Entry, size),
+		Threshold: threshold,
+	}
+}
+
+// Index returns the index of the slot in the hash table where the value should be stored.
+// The error is always nil, and is kept for compatibility with hashmap.Map.
+func (ht *{{.Name}}) Index(key {{.KeyType}}) (index uint32, err error) {
+	return {{.Prefix}}Hash(key) % ht.size, nil
+}
+
+// Resize changes the size of the Map to the next prime after doubling the current size.
+func (ht *{{.Name}}) Resize() {
+	ht.size = {{.Prefix}}NextPrime(ht.size * 2)
+	newData := make([]*{{.Name}}Entry, ht.size)
+
+	// Move the entries to their new chains
+	for _, current := range ht.data {
+		for current != nil {
+			next := current.Next
+			index := {{.Prefix}}Hash(current.Key) % ht.size
+			current.Next = newData[index]
+			newData[index] = current
+			current = next
+		}
+	}
+
+	ht.data = newData
+}
+
+// Set adds an item to the Map.
+func (ht *{{.Name}}) Set(key {{.KeyType}}, value {{.ValueType}}) {
+	if ht.size == 0 {
+		ht.Resize()
+	}
+
+	index := {{.Prefix}}Hash(key) % ht.size
+	newEntry := &{{.Name}}Entry{Key: key, Value: value}
+
+	if ht.data[index] == nil {
+		ht.data[index] = newEntry
+
+		if ht.LoadFactor() >= ht.Threshold {
+			ht.Resize()
+		}
+
+		return
+	}
+
+	for current := ht.data[index]; current != nil; current = current.Next {
+		if {{.Prefix}}KeyEqual(current.Key, key) {
+			current.Value = value
+			return
+		}
+	}
+
+	newEntry.Next = ht.data[index]
+	ht.data[index] = newEntry
+}
+
+// Get returns the value associated with the key.
+func (ht *{{.Name}}) Get(key {{.KeyType}}) (value {{.ValueType}}, ok bool) {
+	if ht.size == 0 {
+		return value, false
+	}
+
+	for current := ht.data[{{.Prefix}}Hash(key)%ht.size]; current != nil; current = current.Next {
+		if {{.Prefix}}KeyEqual(current.Key, key) {
+			return current.Value, true
+		}
+	}
+
+	return value, false
+}
+
+// Delete removes an item from the Map.
+func (ht *{{.Name}}) Delete(key {{.KeyType}}) {
+	if ht.size == 0 {
+		return
+	}
+
+	for link := &ht.data[{{.Prefix}}Hash(key)%ht.size]; *link != nil; link = &(*link).Next {
+		if {{.Prefix}}KeyEqual((*link).Key, key) {
+			*link = (*link).Next
+			return
+		}
+	}
+}
+
+// Len returns the number of items in the Map.
+func (ht *{{.Name}}) Len() int {
+	count := 0
+
+	for _, current := range ht.data {
+		for ; current != nil; current = current.Next {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Size returns the size of the Map.
+func (ht *{{.Name}}) Size() uint32 {
+	return ht.size
+}
+
+// LoadFactor returns the load factor of the Map.
+func (ht *{{.Name}}) LoadFactor() float32 { return float32(ht.Len()) / float32(ht.size) }
+
+// Clear removes all items from the Map.
+func (ht *{{.Name}}) Clear() {
+	ht.data = make([]*{{.Name}}Entry, ht.size)
+}
+
+// Keys return a slice of all keys in the Map.
+func (ht *{{.Name}}) Keys() []{{.KeyType}} {
+	keys := make([]{{.KeyType}}, 0)
+
+	for _, current := range ht.data {
+		for ; current != nil; current = current.Next {
+			keys = append(keys, current.Key)
+		}
+	}
+
+	return keys
+}
+
+// Values return a slice of all values in the Map.
+func (ht *{{.Name}}) Values() []{{.ValueType}} {
+	values := make([]{{.ValueType}}, 0)
+
+	for _, current := range ht.data {
+		for ; current != nil; current = current.Next {
+			values = append(values, current.Value)
+		}
+	}
+
+	return values
+}
+
+// Iter returns a channel that iterates over all items in the Map.
+func (ht *{{.Name}}) Iter() <-chan {{.Name}}Entry {
+	ch := make(chan {{.Name}}Entry)
+
+	go func() {
+		for _, current := range ht.data {
+			for ; current != nil; current = current.Next {
+				ch <- *current
+			}
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+// All returns an iterator over all items in the Map.
+// The Map must not be modified during the iteration.
+func (ht *{{.Name}}) All() iter.Seq2[{{.KeyType}}, {{.ValueType}}] {
+	return func(yield func({{.KeyType}}, {{.ValueType}}) bool) {
+		for _, current := range ht.data {
+			for ; current != nil; current = current.Next {
+				if !yield(current.Key, current.Value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Equal returns true if the Map is equal to another Map.
+func (ht *{{.Name}}) Equal(other *{{.Name}}) bool {
+	if ht.Len() != other.Len() {
+		return false
+	}
+
+	for _, current := range ht.data {
+		for ; current != nil; current = current.Next {
+			value, ok := other.Get(current.Key)
+
+			if !ok || !{{.Prefix}}ValueEqual(value, current.Value) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// String returns a string representation of the Map.
+func (ht *{{.Name}}) String() string {
+	str := "{"
+
+	for _, current := range ht.data {
+		for ; current != nil; current = current.Next {
+			str += fmt.Sprintf("%v: %v, ", current.Key, current.Value)
+		}
+	}
+
+	// Remove the trailing comma and space
+	if len(str) > 1 {
+		str = str[:len(str)-2]
+	}
+
+	return str + "}"
+}
+
+// {{.Prefix}}Hash returns the FNV-1a hash of key.
+func {{.Prefix}}Hash(key {{.KeyType}}) uint32 {
+	h := uint32(2166136261)
+	{{.HashKey}}
+	return h
+}
+
+// {{.Prefix}}KeyEqual returns true if the keys a and b are equal.
+func {{.Prefix}}KeyEqual(a, b {{.KeyType}}) bool {
+	return {{.EqualKeys}}
+}
+
+// {{.Prefix}}ValueEqual returns true if the values a and b are equal.
+func {{.Prefix}}ValueEqual(a, b {{.ValueType}}) bool {
+	return {{.EqualValues}}
+}
+
+// {{.Prefix}}HashUint64 mixes the bytes of v into the running hash h.
+func {{.Prefix}}HashUint64(h uint32, v uint64) uint32 {
+	for i := 0; i < 8; i++ {
+		h ^= uint32(byte(v >> (8 * i)))
+		h *= 16777619
+	}
+	return h
+}
+
+// {{.Prefix}}HashString mixes the length and the bytes of s into the running hash h.
+func {{.Prefix}}HashString(h uint32, s string) uint32 {
+	h = {{.Prefix}}HashUint64(h, uint64(len(s)))
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= 16777619
+	}
+	return h
+}
+
+// {{.Prefix}}HashBool mixes b into the running hash h.
+func {{.Prefix}}HashBool(h uint32, b bool) uint32 {
+	if b {
+		return {{.Prefix}}HashUint64(h, 1)
+	}
+	return {{.Prefix}}HashUint64(h, 0)
+}
+
+// {{.Prefix}}HashFloat mixes f into the running hash h.
+// Zero is normalized, since 0 and -0 are equal but have different bits.
+func {{.Prefix}}HashFloat(h uint32, f float64) uint32 {
+	if f == 0 {
+		f = 0
+	}
+	return {{.Prefix}}HashUint64(h, math.Float64bits(f))
+}
+
+// {{.Prefix}}NextPrime returns the next prime number after n.
+func {{.Prefix}}NextPrime(n uint32) uint32 {
+	for n++; ; n++ {
+		prime := n > 1
+		for i := uint32(2); prime && i*i <= n; i++ {
+			prime = n%i != 0
+		}
+		if prime {
+			return n
+		}
+	}
+}
+`))
+
+var testTemplate = template.Must(template.New("test").Parse(`// Code generated by hashmapgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	{{- if .UsesStrconv}}
+	"strconv"
+	{{- end}}
+	"testing"
+)
+
+// {{.Prefix}}Samples returns n sample entries, keeping the last value of each distinct key.
+func {{.Prefix}}Samples(n int) (keys []{{.KeyType}}, values []{{.ValueType}}) {
+	for i := 0; i < n; i++ {
+		key, value := {{.SampleKey}}, {{.SampleValue}}
+
+		found := false
+		for j := range keys {
+			if {{.Prefix}}KeyEqual(keys[j], key) {
+				values[j] = value
+				found = true
+			}
+		}
+
+		if !found {
+			keys = append(keys, key)
+			values = append(values, value)
+		}
+	}
+
+	return keys, values
+}
+
+func Test{{.Name}}_Set(t *testing.T) {
+	m := New{{.Name}}(2, 0.75)
+	keys, values := {{.Prefix}}Samples(64)
+
+	for i := 0; i < 64; i++ {
+		m.Set({{.SampleKey}}, {{.SampleValue}})
+	}
+
+	if m.Len() != len(keys) {
+		t.Errorf("Expected length to be %d, got %d", len(keys), m.Len())
+	}
+
+	for i, key := range keys {
+		value, ok := m.Get(key)
+		if !ok || !{{.Prefix}}ValueEqual(value, values[i]) {
+			t.Errorf("Expected to get %v for key %v, got %v", values[i], key, value)
+		}
+	}
+}
+
+func Test{{.Name}}_Set_SizeZero(t *testing.T) {
+	m := New{{.Name}}(0, 0.75)
+	keys, values := {{.Prefix}}Samples(1)
+
+	if _, ok := m.Get(keys[0]); ok {
+		t.Errorf("Expected empty map to not contain %v", keys[0])
+	}
+
+	m.Set(keys[0], values[0])
+
+	if m.Len() != 1 || m.Size() != 2 {
+		t.Errorf("Expected length 1 and size 2, got %d and %d", m.Len(), m.Size())
+	}
+}
+
+func Test{{.Name}}_Index(t *testing.T) {
+	m := New{{.Name}}(11, 0.75)
+
+	for i := 0; i < 64; i++ {
+		a, _ := m.Index({{.SampleKey}})
+		b, _ := m.Index({{.SampleKey}})
+
+		if a != b {
+			t.Errorf("Expected equal keys to have the same index, got %d and %d", a, b)
+		}
+	}
+}
+
+func Test{{.Name}}_Delete(t *testing.T) {
+	m := New{{.Name}}(2, 0.75)
+	keys, values := {{.Prefix}}Samples(64)
+
+	for i, key := range keys {
+		m.Set(key, values[i])
+	}
+
+	for i := 0; i < len(keys); i += 2 {
+		m.Delete(keys[i])
+	}
+
+	for i, key := range keys {
+		if _, ok := m.Get(key); ok != (i%2 == 1) {
+			t.Errorf("Expected presence of %v to be %t", key, i%2 == 1)
+		}
+	}
+
+	if m.Len() != len(keys)/2 {
+		t.Errorf("Expected length to be %d, got %d", len(keys)/2, m.Len())
+	}
+}
+
+func Test{{.Name}}_Resize(t *testing.T) {
+	m := New{{.Name}}(2, 100)
+	keys, values := {{.Prefix}}Samples(64)
+
+	for i, key := range keys {
+		m.Set(key, values[i])
+	}
+
+	m.Resize()
+	m.Resize()
+
+	if m.Size() != 11 {
+		t.Errorf("Expected size to be 11, got %d", m.Size())
+	}
+
+	for i, key := range keys {
+		if value, ok := m.Get(key); !ok || !{{.Prefix}}ValueEqual(value, values[i]) {
+			t.Errorf("Expected to get %v for key %v after resize, got %v", values[i], key, value)
+		}
+	}
+}
+
+func Test{{.Name}}_Equal(t *testing.T) {
+	m1 := New{{.Name}}(2, 0.75)
+	m2 := New{{.Name}}(7, 0.5)
+	keys, values := {{.Prefix}}Samples(64)
+
+	for i, key := range keys {
+		m1.Set(key, values[i])
+		m2.Set(keys[len(keys)-1-i], values[len(keys)-1-i])
+	}
+
+	if !m1.Equal(m2) {
+		t.Errorf("Expected maps to be equal")
+	}
+
+	m2.Delete(keys[0])
+
+	if m1.Equal(m2) {
+		t.Errorf("Expected maps to be different")
+	}
+}
+
+func Test{{.Name}}_Clear(t *testing.T) {
+	m := New{{.Name}}(2, 0.75)
+	keys, values := {{.Prefix}}Samples(64)
+
+	for i, key := range keys {
+		m.Set(key, values[i])
+	}
+
+	count := 0
+	for range m.All() {
+		count++
+	}
+
+	if count != len(keys) || len(m.Keys()) != len(keys) || len(m.Values()) != len(keys) {
+		t.Errorf("Expected %d items, keys and values, got %d, %d and %d", len(keys), count, len(m.Keys()), len(m.Values()))
+	}
+
+	m.Clear()
+
+	if m.Len() != 0 {
+		t.Errorf("Expected length to be 0, got %d", m.Len())
+	}
+}
+`))
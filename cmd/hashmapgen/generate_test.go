@@ -0,0 +1,145 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_MissingFlags(t *testing.T) {
+	if err := run("testdata/shapes", "", "int", "", "", true); err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+}
+
+func TestResolver_Unsupported(t *testing.T) {
+	r, err := newResolver("testdata/shapes")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{"Unsupported", "map[int]int", "*Point", "Missing", "fmt.Stringer"} {
+		if _, err := r.resolveString(s); err == nil {
+			t.Errorf("Expected error resolving %s, got nil", s)
+		}
+	}
+}
+
+func TestResolver_Recursive(t *testing.T) {
+	r, err := newResolver("testdata/shapes")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node, err := r.resolveString("Node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if node.fields[1].typ.elem != node {
+		t.Errorf("Expected Node.Children to hold Node")
+	}
+
+	tree, err := r.resolveString("Tree")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tree.kind != kindSlice || tree.elem != tree {
+		t.Errorf("Expected Tree to be a slice of Tree")
+	}
+}
+
+func TestResolver_InvalidRecursive(t *testing.T) {
+	dir := t.TempDir()
+	src := "package cycle\n\ntype A B\n\ntype B A\n"
+	if err := os.WriteFile(filepath.Join(dir, "cycle.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := newResolver(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.resolveString("A"); err == nil {
+		t.Errorf("Expected error resolving A, got nil")
+	}
+}
+
+func TestNewGenerator_IDs(t *testing.T) {
+	r, err := newResolver("testdata/shapes")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k, _ := r.resolveString("Shape")
+	v, _ := r.resolveString("[]Label")
+	g := newGenerator(config{Package: r.pkg, Name: "ShapeMap", Key: k, Value: v})
+
+	ids := make(map[string]bool)
+	for _, typ := range g.allTypes {
+		if ids[typ.id] {
+			t.Errorf("Expected unique ids, got %s twice", typ.id)
+		}
+		ids[typ.id] = true
+	}
+
+	if len(g.allTypes) <= len(g.keyTypes) {
+		t.Errorf("Expected value types to be collected after key types")
+	}
+}
+
+// TestRun_Generated generates several Maps into a copy of testdata/shapes,
+// then vets and runs their generated tests.
+func TestRun_Generated(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping go test of the generated code in short mode")
+	}
+
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+
+	dir := t.TempDir()
+	src, err := os.ReadFile("testdata/shapes/shapes.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "shapes.go"), src, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module shapes\n\ngo 1.23\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	maps := []struct{ key, value, name string }{
+		{"Point", "string", ""},
+		{"Shape", "Point", ""},
+		{"Node", "[]Label", "NodeLabels"},
+		{"string", "int", "StringMap"},
+		{"Label", "Shape", ""},
+		{"[3]float32", "bool", "VectorMap"},
+		{"bool", "complex128", "BoolMap"},
+		{"string", "Tree", "TreeMap"},
+	}
+
+	for _, m := range maps {
+		if err := run(dir, m.key, m.value, m.name, "", true); err != nil {
+			t.Fatalf("generating %s -> %s: %v", m.key, m.value, err)
+		}
+	}
+
+	for _, args := range [][]string{{"vet", "./..."}, {"test", "./..."}} {
+		cmd := exec.Command(gobin, args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("go %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+}
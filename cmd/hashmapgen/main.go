@@ -0,0 +1,83 @@
+// Command hashmapgen generates a Map specialized for a key and a value type.
+//
+// The generated Map exposes the same methods as hashmap.Map, but hashes and compares
+// keys with code generated field by field instead of encoding/json and reflect.
+// Key and value types can be predeclared types, or structs, arrays and slices of
+// supported types declared in the package the code is generated into.
+//
+// Usage, from a file of the target package:
+//
+//	//go:generate go run github.com/pietroagazzi/gohashlib/cmd/hashmapgen -key Point -value string -type PointMap
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	key := flag.String("key", "", "key type (required)")
+	value := flag.String("value", "", "value type (required)")
+	name := flag.String("type", "", "name of the generated Map type (default <key>Map)")
+	dir := flag.String("dir", ".", "directory of the package the code is generated into")
+	output := flag.String("output", "", "output file name (default <type>_gen.go)")
+	tests := flag.Bool("tests", true, "also generate the tests of the Map")
+	flag.Parse()
+
+	if err := run(*dir, *key, *value, *name, *output, *tests); err != nil {
+		fmt.Fprintln(os.Stderr, "hashmapgen:", err)
+		os.Exit(1)
+	}
+}
+
+// run generates the Map and writes it, along with its tests, to dir.
+func run(dir, key, value, name, output string, tests bool) error {
+	if key == "" || value == "" {
+		return fmt.Errorf("-key and -value are required")
+	}
+
+	if name == "" {
+		name = exportName(sanitize(key)) + "Map"
+	}
+	if output == "" {
+		output = strings.ToLower(name) + "_gen.go"
+	}
+
+	r, err := newResolver(dir)
+	if err != nil {
+		return err
+	}
+
+	k, err := r.resolveString(key)
+	if err != nil {
+		return fmt.Errorf("key: %w", err)
+	}
+	v, err := r.resolveString(value)
+	if err != nil {
+		return fmt.Errorf("value: %w", err)
+	}
+
+	g := newGenerator(config{Package: r.pkg, Name: name, Key: k, Value: v})
+
+	src, err := g.Source()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, output), src, 0o644); err != nil {
+		return err
+	}
+
+	if !tests {
+		return nil
+	}
+
+	src, err = g.TestSource()
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, strings.TrimSuffix(output, ".go")+"_test.go"), src, 0o644)
+}
@@ -0,0 +1,31 @@
+package shapes
+
+type Point struct {
+	X, Y int
+	_    int
+}
+
+type Label string
+
+type Shape struct {
+	Name   Label
+	Origin Point
+	Points []Point
+	Tags   [2]string
+	Scale  float64
+	Hidden bool
+	meta   struct {
+		Version uint8
+	}
+}
+
+type Node struct {
+	ID       int64
+	Children []Node
+}
+
+type Tree []Tree
+
+type Unsupported struct {
+	Next *Unsupported
+}
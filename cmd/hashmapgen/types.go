@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// kind is the shape of a type the generator knows how to hash and compare.
+type kind int
+
+const (
+	kindBasic kind = iota
+	kindStruct
+	kindArray
+	kindSlice
+)
+
+// typeInfo describes a key or value type, or one of the types reachable from it.
+type typeInfo struct {
+	kind kind
+	// expr is the type as written in the generated code
+	expr string
+	// id is used to name the helpers generated for the type
+	id string
+	// basic is the underlying predeclared type of a kindBasic type
+	basic string
+	// elem is the element type of arrays and slices
+	elem *typeInfo
+	// fields are the fields of a struct
+	fields []fieldInfo
+}
+
+// fieldInfo is a single struct field.
+type fieldInfo struct {
+	name string
+	typ  *typeInfo
+}
+
+// basicTypes are the predeclared types supported by the generator.
+var basicTypes = map[string]bool{
+	"bool": true, "string": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"byte": true, "rune": true,
+	"float32": true, "float64": true,
+	"complex64": true, "complex128": true,
+}
+
+// resolver turns type expressions into typeInfo, looking up named types in the
+// declarations of a single package.
+type resolver struct {
+	pkg   string
+	decls map[string]ast.Expr
+	named map[string]*typeInfo
+	anon  int
+}
+
+// newResolver parses the non-test Go files in dir and collects their type declarations.
+func newResolver(dir string) (*resolver, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &resolver{decls: make(map[string]ast.Expr), named: make(map[string]*typeInfo)}
+	fset := token.NewFileSet()
+
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+
+		if r.pkg == "" {
+			r.pkg = f.Name.Name
+		}
+
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if ts.TypeParams != nil {
+					continue
+				}
+				r.decls[ts.Name.Name] = ts.Type
+			}
+		}
+	}
+
+	if r.pkg == "" {
+		return nil, fmt.Errorf("no Go files in %s", dir)
+	}
+
+	return r, nil
+}
+
+// resolveString parses and resolves a type written as a string, e.g. a command line flag.
+func (r *resolver) resolveString(s string) (*typeInfo, error) {
+	expr, err := parser.ParseExpr(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid type %q: %w", s, err)
+	}
+
+	return r.resolve(expr)
+}
+
+// resolve returns the typeInfo of a type expression.
+func (r *resolver) resolve(expr ast.Expr) (*typeInfo, error) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return r.resolve(e.X)
+	case *ast.Ident:
+		if basicTypes[e.Name] {
+			return &typeInfo{kind: kindBasic, expr: e.Name, id: exportName(e.Name), basic: e.Name}, nil
+		}
+		return r.resolveNamed(e.Name)
+	case *ast.ArrayType:
+		elem, err := r.resolve(e.Elt)
+		if err != nil {
+			return nil, err
+		}
+		if e.Len == nil {
+			return &typeInfo{kind: kindSlice, expr: types.ExprString(e), id: "SliceOf" + elem.id, elem: elem}, nil
+		}
+		id := "Array" + sanitize(types.ExprString(e.Len)) + "Of" + elem.id
+		return &typeInfo{kind: kindArray, expr: types.ExprString(e), id: id, elem: elem}, nil
+	case *ast.StructType:
+		r.anon++
+		t := &typeInfo{kind: kindStruct, expr: types.ExprString(e), id: fmt.Sprintf("Struct%d", r.anon)}
+		return t, r.resolveFields(t, e)
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", types.ExprString(expr))
+}
+
+// resolveNamed returns the typeInfo of a type declared in the package.
+//
+// The result is cached before its fields or underlying type are resolved, so recursive
+// types such as a struct holding a slice of itself, or a slice of itself, resolve to
+// a single typeInfo.
+func (r *resolver) resolveNamed(name string) (*typeInfo, error) {
+	if t, ok := r.named[name]; ok {
+		return t, nil
+	}
+
+	decl, ok := r.decls[name]
+	if !ok {
+		return nil, fmt.Errorf("type %s is not declared in package %s", name, r.pkg)
+	}
+
+	if st, ok := decl.(*ast.StructType); ok {
+		t := &typeInfo{kind: kindStruct, expr: name, id: exportName(name)}
+		r.named[name] = t
+		return t, r.resolveFields(t, st)
+	}
+
+	// The placeholder is filled once the underlying type is resolved
+	t := &typeInfo{expr: name, id: exportName(name)}
+	r.named[name] = t
+
+	underlying, err := r.resolve(decl)
+	if err != nil {
+		delete(r.named, name)
+		return nil, fmt.Errorf("type %s: %w", name, err)
+	}
+	// Only a placeholder has no basic type, e.g. in type A B; type B A
+	if underlying.kind == kindBasic && underlying.basic == "" {
+		delete(r.named, name)
+		return nil, fmt.Errorf("type %s: invalid recursive type", name)
+	}
+
+	// A named type shares the shape of its underlying type, but is spelled by its name
+	*t = *underlying
+	t.expr = name
+	t.id = exportName(name)
+
+	return t, nil
+}
+
+// resolveFields fills the fields of a struct typeInfo.
+func (r *resolver) resolveFields(t *typeInfo, st *ast.StructType) error {
+	for _, field := range st.Fields.List {
+		ft, err := r.resolve(field.Type)
+		if err != nil {
+			return fmt.Errorf("type %s: %w", t.expr, err)
+		}
+
+		// Embedded fields are named after their type
+		if len(field.Names) == 0 {
+			ident, ok := field.Type.(*ast.Ident)
+			if !ok {
+				return fmt.Errorf("type %s: unsupported embedded field %s", t.expr, types.ExprString(field.Type))
+			}
+			t.fields = append(t.fields, fieldInfo{name: ident.Name, typ: ft})
+			continue
+		}
+
+		for _, name := range field.Names {
+			if name.Name == "_" {
+				continue
+			}
+			t.fields = append(t.fields, fieldInfo{name: name.Name, typ: ft})
+		}
+	}
+
+	return nil
+}
+
+// exportName upper-cases the first letter of s.
+func exportName(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// sanitize drops the characters of s that are not valid in an identifier.
+func sanitize(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		if c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
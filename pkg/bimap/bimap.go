@@ -0,0 +1,141 @@
+package bimap
+
+import (
+	"errors"
+	"fmt"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/utils"
+)
+
+// ErrValueExists is returned by Set when the value is already associated with another key.
+var ErrValueExists = errors.New("bimap: value already associated with another key")
+
+// BiMap represents a one-to-one Map, where each value is associated with a single key.
+//
+// It keeps two Maps, from keys to values and from values to keys, so that lookups
+// are fast in both directions.
+type BiMap[K, V any] struct {
+	forward  *hashmap.Map[K, V]
+	backward *hashmap.Map[V, K]
+
+	// inverse is the view of the BiMap from values to keys, sharing its Maps
+	inverse *BiMap[V, K]
+}
+
+// NewBiMap returns a new BiMap with the given size and threshold.
+func NewBiMap[K, V any](size uint32, threshold float32) *BiMap[K, V] {
+	bm := &BiMap[K, V]{
+		forward:  hashmap.NewMap[K, V](size, threshold),
+		backward: hashmap.NewMap[V, K](size, threshold),
+	}
+	bm.inverse = &BiMap[V, K]{forward: bm.backward, backward: bm.forward, inverse: bm}
+
+	return bm
+}
+
+// Set associates the key with the value, replacing the previous value of the key.
+//
+// It returns ErrValueExists, leaving the BiMap unchanged, if the value is already
+// associated with another key.
+func (bm *BiMap[K, V]) Set(key K, value V) error {
+	if other, ok := bm.backward.Get(value); ok && !utils.Equaler(other, key) {
+		return ErrValueExists
+	}
+
+	bm.ForceSet(key, value)
+	return nil
+}
+
+// ForceSet associates the key with the value, removing both the previous value of
+// the key and the previous key of the value.
+func (bm *BiMap[K, V]) ForceSet(key K, value V) {
+	bm.Delete(key)
+	bm.DeleteByValue(value)
+
+	bm.forward.Set(key, value)
+	bm.backward.Set(value, key)
+}
+
+// Get returns the value associated with the key.
+func (bm *BiMap[K, V]) Get(key K) (value V, ok bool) {
+	return bm.forward.Get(key)
+}
+
+// GetByValue returns the key associated with the value.
+func (bm *BiMap[K, V]) GetByValue(value V) (key K, ok bool) {
+	return bm.backward.Get(value)
+}
+
+// ContainsKey checks if the key is in the BiMap.
+func (bm *BiMap[K, V]) ContainsKey(key K) bool {
+	_, ok := bm.forward.Get(key)
+	return ok
+}
+
+// ContainsValue checks if the value is in the BiMap.
+func (bm *BiMap[K, V]) ContainsValue(value V) bool {
+	_, ok := bm.backward.Get(value)
+	return ok
+}
+
+// Delete removes the key and its value from the BiMap.
+func (bm *BiMap[K, V]) Delete(key K) {
+	value, ok := bm.forward.Get(key)
+	if !ok {
+		return
+	}
+
+	bm.forward.Delete(key)
+	bm.backward.Delete(value)
+}
+
+// DeleteByValue removes the value and its key from the BiMap.
+func (bm *BiMap[K, V]) DeleteByValue(value V) {
+	bm.inverse.Delete(value)
+}
+
+// Inverse returns the view of the BiMap from values to keys.
+// The view shares the storage of the BiMap, so changes to either are visible in both.
+func (bm *BiMap[K, V]) Inverse() *BiMap[V, K] {
+	return bm.inverse
+}
+
+// Len returns the number of items in the BiMap.
+func (bm *BiMap[K, V]) Len() int {
+	return bm.forward.Len()
+}
+
+// Keys return a slice of all keys in the BiMap.
+func (bm *BiMap[K, V]) Keys() []K {
+	return bm.forward.Keys()
+}
+
+// Values return a slice of all values in the BiMap.
+func (bm *BiMap[K, V]) Values() []V {
+	return bm.backward.Keys()
+}
+
+// Clear removes all items from the BiMap and from its inverse.
+func (bm *BiMap[K, V]) Clear() {
+	bm.forward.Clear()
+	bm.backward.Clear()
+}
+
+// Iter returns a channel that iterates over all items in the BiMap.
+func (bm *BiMap[K, V]) Iter() <-chan hashmap.Entry[K, V] {
+	ch := make(chan hashmap.Entry[K, V])
+
+	go func() {
+		for i := range bm.forward.Iter() {
+			ch <- hashmap.Entry[K, V]{Key: i.Key, Value: i.Value}
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+// String returns a string representation of the BiMap.
+func (bm *BiMap[K, V]) String() string {
+	return fmt.Sprint(bm.forward)
+}
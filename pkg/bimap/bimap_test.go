@@ -0,0 +1,118 @@
+package bimap_test
+
+import (
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/bimap"
+	"testing"
+)
+
+type user struct {
+	Name string
+}
+
+func TestBiMap_Set(t *testing.T) {
+	bm := bimap.NewBiMap[int, user](2, 0.75)
+
+	if err := bm.Set(1, user{"alice"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	key, ok := bm.GetByValue(user{"alice"})
+	if !ok || key != 1 {
+		t.Errorf("Expected to get 1, got %d", key)
+	}
+}
+
+func TestBiMap_Set_Conflict(t *testing.T) {
+	bm := bimap.NewBiMap[int, string](2, 0.75)
+	_ = bm.Set(1, "one")
+
+	if err := bm.Set(2, "one"); !errors.Is(err, bimap.ErrValueExists) {
+		t.Errorf("Expected ErrValueExists, got %v", err)
+	}
+	if bm.ContainsKey(2) || bm.Len() != 1 {
+		t.Errorf("Expected BiMap to be unchanged after a conflict")
+	}
+
+	// Setting the same pair again is not a conflict
+	if err := bm.Set(1, "one"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestBiMap_Set_ReplaceValue(t *testing.T) {
+	bm := bimap.NewBiMap[int, string](2, 0.75)
+	_ = bm.Set(1, "one")
+	_ = bm.Set(1, "uno")
+
+	if bm.ContainsValue("one") {
+		t.Errorf("Expected the previous value of the key to be removed")
+	}
+	if value, _ := bm.Get(1); value != "uno" {
+		t.Errorf("Expected to get uno, got %s", value)
+	}
+}
+
+func TestBiMap_ForceSet(t *testing.T) {
+	bm := bimap.NewBiMap[int, string](2, 0.75)
+	_ = bm.Set(1, "one")
+	_ = bm.Set(2, "two")
+
+	bm.ForceSet(1, "two")
+
+	if bm.ContainsKey(2) || bm.ContainsValue("one") || bm.Len() != 1 {
+		t.Errorf("Expected ForceSet to remove conflicting pairs, got %v", bm)
+	}
+	if key, _ := bm.GetByValue("two"); key != 1 {
+		t.Errorf("Expected to get 1, got %d", key)
+	}
+}
+
+func TestBiMap_Delete(t *testing.T) {
+	bm := bimap.NewBiMap[int, string](2, 0.75)
+	_ = bm.Set(1, "one")
+	_ = bm.Set(2, "two")
+
+	bm.Delete(1)
+	bm.DeleteByValue("two")
+
+	if bm.Len() != 0 || bm.Inverse().Len() != 0 {
+		t.Errorf("Expected BiMap to be empty, got %v", bm)
+	}
+}
+
+func TestBiMap_Inverse(t *testing.T) {
+	bm := bimap.NewBiMap[int, string](2, 0.75)
+	inverse := bm.Inverse()
+
+	_ = inverse.Set("one", 1)
+
+	if value, ok := bm.Get(1); !ok || value != "one" {
+		t.Errorf("Expected changes to the inverse to be visible, got %s", value)
+	}
+	if inverse.Inverse() != bm {
+		t.Errorf("Expected the inverse of the inverse to be the BiMap")
+	}
+
+	bm.Clear()
+
+	if inverse.Len() != 0 || inverse.ContainsKey("one") {
+		t.Errorf("Expected Clear to empty the inverse")
+	}
+}
+
+func TestBiMap_Iter(t *testing.T) {
+	bm := bimap.NewBiMap[int, int](2, 0.75)
+	_ = bm.Set(1, 10)
+	_ = bm.Set(2, 20)
+
+	for e := range bm.Iter() {
+		if e.Value != e.Key*10 {
+			t.Errorf("Unexpected pair %d: %d", e.Key, e.Value)
+		}
+	}
+
+	if len(bm.Keys()) != 2 || len(bm.Values()) != 2 {
+		t.Errorf("Expected 2 keys and values")
+	}
+}
@@ -0,0 +1,28 @@
+package counter
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+)
+
+// FromSet returns a new Counter holding each value of the set once.
+func FromSet[T any](s *set.Set[T]) *Counter[T] {
+	c := NewCounter[T](s.Size(), hashmap.DefaultThreshold)
+
+	for value := range s.Iter() {
+		c.Add(value, 1)
+	}
+
+	return c
+}
+
+// Distinct returns a set containing the distinct values of the Counter.
+func (c *Counter[T]) Distinct() *set.Set[T] {
+	s := set.NewSet[T](c.m.Size(), c.m.Threshold)
+
+	for i := range c.m.Iter() {
+		s.Add(i.Key)
+	}
+
+	return s
+}
@@ -0,0 +1,30 @@
+package counter_test
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/counter"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"testing"
+)
+
+func TestFromSet(t *testing.T) {
+	s := set.NewSet[int](2, 0.75)
+	s.Add(1, 2, 3)
+
+	c := counter.FromSet(s)
+
+	if c.Len() != 3 || c.Total() != 3 || c.Count(2) != 1 {
+		t.Errorf("Expected each value once, got %v", c)
+	}
+}
+
+func TestCounter_Distinct(t *testing.T) {
+	c := counter.NewCounter[int](2, 0.75)
+	c.Add(1, 3)
+	c.Add(2, 1)
+
+	s := c.Distinct()
+
+	if s.Len() != 2 || !s.Contains(1) || !s.Contains(2) {
+		t.Errorf("Expected {1, 2}, got %v", s)
+	}
+}
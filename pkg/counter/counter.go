@@ -0,0 +1,116 @@
+package counter
+
+import (
+	"fmt"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+)
+
+// Counter represents a multiset, counting the occurrences of each value.
+// Values are only stored while their count is positive.
+type Counter[T any] struct {
+	m *hashmap.Map[T, int]
+
+	// total is the sum of all counts
+	total int
+}
+
+// NewCounter returns a new Counter with the given size and threshold.
+func NewCounter[T any](size uint32, threshold float32) *Counter[T] {
+	return &Counter[T]{m: hashmap.NewMap[T, int](size, threshold)}
+}
+
+// Add adds n occurrences of the value.
+// A negative n removes occurrences, as Remove does.
+func (c *Counter[T]) Add(value T, n int) {
+	if n < 0 {
+		c.Remove(value, -n)
+		return
+	}
+	if n == 0 {
+		return
+	}
+
+	count, _ := c.m.Get(value)
+	c.m.Set(value, count+n)
+	c.total += n
+}
+
+// Remove removes n occurrences of the value.
+// The value is removed from the Counter when its count drops to zero.
+func (c *Counter[T]) Remove(value T, n int) {
+	if n < 0 {
+		c.Add(value, -n)
+		return
+	}
+
+	count, ok := c.m.Get(value)
+	if !ok || n == 0 {
+		return
+	}
+
+	if n >= count {
+		c.m.Delete(value)
+		c.total -= count
+		return
+	}
+
+	c.m.Set(value, count-n)
+	c.total -= n
+}
+
+// Count returns the number of occurrences of the value.
+func (c *Counter[T]) Count(value T) int {
+	count, _ := c.m.Get(value)
+	return count
+}
+
+// Total returns the sum of all counts.
+func (c *Counter[T]) Total() int {
+	return c.total
+}
+
+// Len returns the number of distinct values in the Counter.
+func (c *Counter[T]) Len() int {
+	return c.m.Len()
+}
+
+// Clear removes all values from the Counter.
+func (c *Counter[T]) Clear() {
+	c.m.Clear()
+	c.total = 0
+}
+
+// Copy returns a copy of the Counter.
+func (c *Counter[T]) Copy() *Counter[T] {
+	result := NewCounter[T](c.m.Size(), c.m.Threshold)
+
+	for i := range c.m.Iter() {
+		result.Add(i.Key, i.Value)
+	}
+
+	return result
+}
+
+// Iter returns a channel that iterates over all values in the Counter, along with their count.
+func (c *Counter[T]) Iter() <-chan hashmap.Entry[T, int] {
+	ch := make(chan hashmap.Entry[T, int])
+
+	go func() {
+		for i := range c.m.Iter() {
+			ch <- hashmap.Entry[T, int]{Key: i.Key, Value: i.Value}
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+// Equal returns true if both Counters hold the same values with the same counts.
+func (c *Counter[T]) Equal(other *Counter[T]) bool {
+	return c.total == other.total && c.m.Equal(other.m)
+}
+
+// String returns a string representation of the Counter.
+func (c *Counter[T]) String() string {
+	return fmt.Sprint(c.m)
+}
@@ -0,0 +1,73 @@
+package counter_test
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/counter"
+	"testing"
+)
+
+type point struct {
+	X, Y int
+}
+
+func TestCounter_Add(t *testing.T) {
+	c := counter.NewCounter[point](2, 0.75)
+	c.Add(point{1, 2}, 3)
+	c.Add(point{1, 2}, 2)
+	c.Add(point{3, 4}, 1)
+	c.Add(point{5, 6}, 0)
+
+	if c.Count(point{1, 2}) != 5 {
+		t.Errorf("Expected count to be 5, got %d", c.Count(point{1, 2}))
+	}
+	if c.Total() != 6 || c.Len() != 2 {
+		t.Errorf("Expected total 6 and length 2, got %d and %d", c.Total(), c.Len())
+	}
+}
+
+func TestCounter_Remove(t *testing.T) {
+	c := counter.NewCounter[string](2, 0.75)
+	c.Add("a", 3)
+	c.Add("b", 1)
+
+	c.Remove("a", 1)
+	if c.Count("a") != 2 || c.Total() != 3 {
+		t.Errorf("Expected count 2 and total 3, got %d and %d", c.Count("a"), c.Total())
+	}
+
+	c.Remove("a", 5)
+	c.Remove("c", 1)
+	if c.Count("a") != 0 || c.Len() != 1 || c.Total() != 1 {
+		t.Errorf("Expected a to be removed, got %v", c)
+	}
+
+	c.Add("b", -1)
+	if c.Len() != 0 || c.Total() != 0 {
+		t.Errorf("Expected counter to be empty, got %v", c)
+	}
+}
+
+func TestCounter_Clear(t *testing.T) {
+	c := counter.NewCounter[int](2, 0.75)
+	c.Add(1, 2)
+	c.Clear()
+
+	if c.Len() != 0 || c.Total() != 0 {
+		t.Errorf("Expected counter to be empty after Clear")
+	}
+}
+
+func TestCounter_Equal(t *testing.T) {
+	c1 := counter.NewCounter[int](2, 0.75)
+	c1.Add(1, 2)
+	c1.Add(2, 1)
+
+	c2 := c1.Copy()
+	if !c1.Equal(c2) {
+		t.Errorf("Expected copy to be equal")
+	}
+
+	c2.Add(2, 1)
+	if c1.Equal(c2) {
+		t.Errorf("Expected counters to be different")
+	}
+}
@@ -0,0 +1,50 @@
+package counter
+
+import "github.com/pietroagazzi/gohashlib/pkg/hashmap"
+
+// Sum returns a new Counter where the count of each value is the sum of its counts in both Counters.
+func (c *Counter[T]) Sum(other *Counter[T]) *Counter[T] {
+	result := c.Copy()
+
+	for i := range other.m.Iter() {
+		result.Add(i.Key, i.Value)
+	}
+
+	return result
+}
+
+// Subtract returns a new Counter where the count of each value is its count in the first Counter
+// minus its count in the second Counter. Values with a count of zero or less are dropped.
+func (c *Counter[T]) Subtract(other *Counter[T]) *Counter[T] {
+	result := c.Copy()
+
+	for i := range other.m.Iter() {
+		result.Remove(i.Key, i.Value)
+	}
+
+	return result
+}
+
+// Intersection returns a new Counter where the count of each value is the minimum of its counts in both Counters.
+func (c *Counter[T]) Intersection(other *Counter[T]) *Counter[T] {
+	result := NewCounter[T](c.m.Size(), hashmap.DefaultThreshold)
+
+	for i := range c.m.Iter() {
+		result.Add(i.Key, min(i.Value, other.Count(i.Key)))
+	}
+
+	return result
+}
+
+// Union returns a new Counter where the count of each value is the maximum of its counts in both Counters.
+func (c *Counter[T]) Union(other *Counter[T]) *Counter[T] {
+	result := c.Copy()
+
+	for i := range other.m.Iter() {
+		if count := result.Count(i.Key); i.Value > count {
+			result.Add(i.Key, i.Value-count)
+		}
+	}
+
+	return result
+}
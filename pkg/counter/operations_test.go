@@ -0,0 +1,55 @@
+package counter_test
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/counter"
+	"testing"
+)
+
+func newCounters() (*counter.Counter[string], *counter.Counter[string]) {
+	c1 := counter.NewCounter[string](2, 0.75)
+	c1.Add("a", 3)
+	c1.Add("b", 1)
+
+	c2 := counter.NewCounter[string](2, 0.75)
+	c2.Add("a", 1)
+	c2.Add("b", 2)
+	c2.Add("c", 1)
+
+	return c1, c2
+}
+
+func TestCounter_Sum(t *testing.T) {
+	c1, c2 := newCounters()
+	sum := c1.Sum(c2)
+
+	if sum.Count("a") != 4 || sum.Count("b") != 3 || sum.Count("c") != 1 || sum.Total() != 8 {
+		t.Errorf("Unexpected sum %v", sum)
+	}
+}
+
+func TestCounter_Subtract(t *testing.T) {
+	c1, c2 := newCounters()
+	difference := c1.Subtract(c2)
+
+	if difference.Count("a") != 2 || difference.Len() != 1 || difference.Total() != 2 {
+		t.Errorf("Unexpected difference %v", difference)
+	}
+}
+
+func TestCounter_Intersection(t *testing.T) {
+	c1, c2 := newCounters()
+	intersection := c1.Intersection(c2)
+
+	if intersection.Count("a") != 1 || intersection.Count("b") != 1 || intersection.Len() != 2 {
+		t.Errorf("Unexpected intersection %v", intersection)
+	}
+}
+
+func TestCounter_Union(t *testing.T) {
+	c1, c2 := newCounters()
+	union := c1.Union(c2)
+
+	if union.Count("a") != 3 || union.Count("b") != 2 || union.Count("c") != 1 || union.Total() != 6 {
+		t.Errorf("Unexpected union %v", union)
+	}
+}
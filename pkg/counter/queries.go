@@ -0,0 +1,56 @@
+package counter
+
+import (
+	"container/heap"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"sort"
+)
+
+// MostCommon returns the k values with the highest counts, from the most to the least common.
+// If k is negative or greater than the number of distinct values, all values are returned.
+//
+// It keeps a min-heap of the k most common values seen so far, so it takes O(n log k) time.
+func (c *Counter[T]) MostCommon(k int) []hashmap.Entry[T, int] {
+	if k < 0 || k > c.Len() {
+		k = c.Len()
+	}
+	if k == 0 {
+		return []hashmap.Entry[T, int]{}
+	}
+
+	h := make(entryHeap[T], 0, k)
+
+	for i := range c.m.Iter() {
+		e := hashmap.Entry[T, int]{Key: i.Key, Value: i.Value}
+
+		if len(h) < k {
+			heap.Push(&h, e)
+		} else if e.Value > h[0].Value {
+			h[0] = e
+			heap.Fix(&h, 0)
+		}
+	}
+
+	result := []hashmap.Entry[T, int](h)
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Value > result[j].Value })
+
+	return result
+}
+
+// entryHeap is a min-heap of entries, ordered by count.
+type entryHeap[T any] []hashmap.Entry[T, int]
+
+func (h entryHeap[T]) Len() int           { return len(h) }
+func (h entryHeap[T]) Less(i, j int) bool { return h[i].Value < h[j].Value }
+func (h entryHeap[T]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *entryHeap[T]) Push(x any) {
+	*h = append(*h, x.(hashmap.Entry[T, int]))
+}
+
+func (h *entryHeap[T]) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,32 @@
+package counter_test
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/counter"
+	"testing"
+)
+
+func TestCounter_MostCommon(t *testing.T) {
+	c := counter.NewCounter[int](2, 0.75)
+	for i := 1; i <= 10; i++ {
+		c.Add(i, i)
+	}
+
+	top := c.MostCommon(3)
+
+	if len(top) != 3 || top[0].Key != 10 || top[1].Key != 9 || top[2].Key != 8 {
+		t.Errorf("Expected 10, 9 and 8, got %v", top)
+	}
+}
+
+func TestCounter_MostCommon_All(t *testing.T) {
+	c := counter.NewCounter[int](2, 0.75)
+	c.Add(1, 1)
+	c.Add(2, 2)
+
+	if top := c.MostCommon(-1); len(top) != 2 || top[0].Key != 2 {
+		t.Errorf("Expected all values, got %v", top)
+	}
+	if top := c.MostCommon(0); len(top) != 0 {
+		t.Errorf("Expected no values, got %v", top)
+	}
+}
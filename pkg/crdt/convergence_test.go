@@ -0,0 +1,239 @@
+package crdt_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pietroagazzi/gohashlib/pkg/crdt"
+	"math/rand"
+	"testing"
+)
+
+// converge checks that replicas converge both when exchanging full states and deltas,
+// and when exchanging deltas only.
+func converge[R crdt.Mergeable[R]](t *testing.T, newReplica func(name string) R, op func(*rand.Rand, R), equal func(a, b R) bool) {
+	t.Helper()
+
+	convergeStates(t, newReplica, op, equal)
+	convergeDeltas(t, newReplica, op, equal)
+}
+
+// convergeStates replays random operations on three replicas, exchanging random full states
+// through JSON and random deltas, possibly duplicated or lost. After a final exchange of full
+// states, all the replicas must be equal.
+func convergeStates[R crdt.Mergeable[R]](t *testing.T, newReplica func(name string) R, op func(*rand.Rand, R), equal func(a, b R) bool) {
+	t.Helper()
+
+	for seed := int64(0); seed < 20; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+
+		replicas := make([]R, 3)
+		for i := range replicas {
+			replicas[i] = newReplica(fmt.Sprintf("r%d", i))
+		}
+
+		// sync merges the full state of a replica into another, through JSON
+		sync := func(from, to R) {
+			data, err := json.Marshal(from)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			state := newReplica("")
+			if err := json.Unmarshal(data, state); err != nil {
+				t.Fatal(err)
+			}
+			to.Merge(state)
+		}
+
+		for step := 0; step < 200; step++ {
+			r := replicas[rng.Intn(len(replicas))]
+
+			switch rng.Intn(4) {
+			case 0, 1:
+				op(rng, r)
+			case 2:
+				sync(r, replicas[rng.Intn(len(replicas))])
+			case 3:
+				delta := r.Delta()
+				for _, other := range replicas {
+					for n := rng.Intn(3); n > 0; n-- {
+						other.Merge(delta)
+					}
+				}
+			}
+		}
+
+		// Two rounds spread every state to every replica
+		for round := 0; round < 2; round++ {
+			for i, r := range replicas {
+				sync(r, replicas[(i+1)%len(replicas)])
+			}
+		}
+
+		for _, r := range replicas[1:] {
+			if !equal(replicas[0], r) {
+				t.Fatalf("Seed %d: expected replicas to converge through states", seed)
+			}
+		}
+	}
+}
+
+// convergeDeltas replays random operations on three replicas, which only exchange deltas.
+// Every delta reaches every other replica, at a random later step and possibly more than once,
+// so deltas can arrive out of order. Once all the deltas have arrived, all the replicas must
+// be equal.
+func convergeDeltas[R crdt.Mergeable[R]](t *testing.T, newReplica func(name string) R, op func(*rand.Rand, R), equal func(a, b R) bool) {
+	t.Helper()
+
+	for seed := int64(0); seed < 50; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+
+		replicas := make([]R, 3)
+		for i := range replicas {
+			replicas[i] = newReplica(fmt.Sprintf("r%d", i))
+		}
+
+		// pending holds the deltas not yet delivered to each replica
+		pending := make([][]R, len(replicas))
+
+		send := func(from int) {
+			delta := replicas[from].Delta()
+			for to := range replicas {
+				if to != from {
+					pending[to] = append(pending[to], delta)
+				}
+			}
+		}
+
+		// deliver merges a random pending delta into a replica, keeping it pending at times
+		deliver := func(to int) {
+			if len(pending[to]) == 0 {
+				return
+			}
+
+			i := rng.Intn(len(pending[to]))
+			replicas[to].Merge(pending[to][i])
+			if rng.Intn(4) != 0 {
+				pending[to] = append(pending[to][:i], pending[to][i+1:]...)
+			}
+		}
+
+		for step := 0; step < 200; step++ {
+			i := rng.Intn(len(replicas))
+
+			switch rng.Intn(4) {
+			case 0, 1:
+				op(rng, replicas[i])
+			case 2:
+				send(i)
+			case 3:
+				deliver(i)
+			}
+		}
+
+		for i := range replicas {
+			send(i)
+		}
+		for i := range replicas {
+			for _, delta := range pending[i] {
+				replicas[i].Merge(delta)
+			}
+		}
+
+		for _, r := range replicas[1:] {
+			if !equal(replicas[0], r) {
+				t.Fatalf("Seed %d: expected replicas to converge through deltas", seed)
+			}
+		}
+	}
+}
+
+func TestConvergence_GSet(t *testing.T) {
+	converge(t,
+		func(string) *crdt.GSet[int] { return crdt.NewGSet[int]() },
+		func(rng *rand.Rand, s *crdt.GSet[int]) { s.Add(rng.Intn(20)) },
+		func(a, b *crdt.GSet[int]) bool { return a.Value().Equal(b.Value()) },
+	)
+}
+
+func TestConvergence_TwoPSet(t *testing.T) {
+	converge(t,
+		func(string) *crdt.TwoPSet[int] { return crdt.NewTwoPSet[int]() },
+		func(rng *rand.Rand, s *crdt.TwoPSet[int]) {
+			if rng.Intn(2) == 0 {
+				s.Add(rng.Intn(20))
+			} else {
+				s.Remove(rng.Intn(20))
+			}
+		},
+		func(a, b *crdt.TwoPSet[int]) bool { return a.Value().Equal(b.Value()) },
+	)
+}
+
+func TestConvergence_ORSet(t *testing.T) {
+	converge(t,
+		crdt.NewORSet[int],
+		func(rng *rand.Rand, s *crdt.ORSet[int]) {
+			if rng.Intn(2) == 0 {
+				s.Add(rng.Intn(10))
+			} else {
+				s.Remove(rng.Intn(10))
+			}
+		},
+		func(a, b *crdt.ORSet[int]) bool { return a.Value().Equal(b.Value()) },
+	)
+}
+
+func TestConvergence_LWWSet(t *testing.T) {
+	converge(t,
+		crdt.NewLWWSet[int],
+		func(rng *rand.Rand, s *crdt.LWWSet[int]) {
+			if rng.Intn(2) == 0 {
+				s.Add(rng.Intn(10))
+			} else {
+				s.Remove(rng.Intn(10))
+			}
+		},
+		func(a, b *crdt.LWWSet[int]) bool { return a.Value().Equal(b.Value()) },
+	)
+}
+
+func TestConvergence_LWWMap(t *testing.T) {
+	converge(t,
+		crdt.NewLWWMap[int, int],
+		func(rng *rand.Rand, m *crdt.LWWMap[int, int]) {
+			if rng.Intn(3) == 0 {
+				m.Delete(rng.Intn(10))
+			} else {
+				m.Set(rng.Intn(10), rng.Intn(100))
+			}
+		},
+		func(a, b *crdt.LWWMap[int, int]) bool { return a.Value().Equal(b.Value()) },
+	)
+}
+
+func TestConvergence_ORMap(t *testing.T) {
+	converge(t,
+		newPermissions,
+		func(rng *rand.Rand, m *crdt.ORMap[string, *crdt.GSet[string]]) {
+			user := fmt.Sprint("user", rng.Intn(5))
+			if rng.Intn(3) == 0 {
+				m.Remove(user)
+			} else {
+				grant(m, user, fmt.Sprint("permission", rng.Intn(5)))
+			}
+		},
+		func(a, b *crdt.ORMap[string, *crdt.GSet[string]]) bool {
+			if a.Len() != b.Len() {
+				return false
+			}
+			for user, permissions := range a.All() {
+				other, ok := b.Get(user)
+				if !ok || !permissions.Value().Equal(other.Value()) {
+					return false
+				}
+			}
+			return true
+		},
+	)
+}
@@ -0,0 +1,89 @@
+// Package crdt provides conflict-free replicated data types: sets and maps whose replicas
+// can be modified independently and converge to the same state once they have merged
+// each other's changes, in any order and any number of times.
+// https://en.wikipedia.org/wiki/Conflict-free_replicated_data_type
+//
+// Replicas exchange either their whole state, with Merge and the JSON encoding of each type,
+// or delta states holding only their recent changes, with Delta. Elements, keys and values
+// are compared and hashed as hashmap.Map keys are, and must survive a round trip through JSON
+// to be sent to other replicas.
+package crdt
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+)
+
+// Mergeable is the behavior shared by the types of this package.
+// ORMap accepts any Mergeable as its values.
+type Mergeable[T any] interface {
+	// Merge merges the state of another replica, or a delta state, into the replica.
+	Merge(other T)
+	// Delta returns the changes made to the replica since the last call to Delta,
+	// as a state to merge into other replicas.
+	Delta() T
+}
+
+var (
+	_ Mergeable[*GSet[int]]              = (*GSet[int])(nil)
+	_ Mergeable[*TwoPSet[int]]           = (*TwoPSet[int])(nil)
+	_ Mergeable[*ORSet[int]]             = (*ORSet[int])(nil)
+	_ Mergeable[*LWWSet[int]]            = (*LWWSet[int])(nil)
+	_ Mergeable[*LWWMap[int, int]]       = (*LWWMap[int, int])(nil)
+	_ Mergeable[*ORMap[int, *GSet[int]]] = (*ORMap[int, *GSet[int]])(nil)
+
+	_ set.Interface[int]          = (*ORSet[int])(nil)
+	_ set.Interface[int]          = (*LWWSet[int])(nil)
+	_ hashmap.Interface[int, int] = (*LWWMap[int, int])(nil)
+)
+
+// Timestamp orders the operations of LWWSet and LWWMap.
+//
+// It is a Lamport clock: a replica increments its counter on every operation, and advances
+// it past the counters it merges, so an operation made after observing another one is
+// always later. Concurrent operations are ordered by the name of their replica.
+type Timestamp struct {
+	Counter uint64 `json:"counter"`
+	Replica string `json:"replica"`
+}
+
+// After reports whether the timestamp is later than another one.
+func (ts Timestamp) After(other Timestamp) bool {
+	if ts.Counter != other.Counter {
+		return ts.Counter > other.Counter
+	}
+	return ts.Replica > other.Replica
+}
+
+// clock issues the timestamps of a replica.
+type clock struct {
+	replica string
+	counter uint64
+}
+
+// tick returns the timestamp of a new operation.
+func (c *clock) tick() Timestamp {
+	c.counter++
+	return Timestamp{Counter: c.counter, Replica: c.replica}
+}
+
+// observe advances the clock past a timestamp of another replica.
+func (c *clock) observe(ts Timestamp) {
+	c.counter = max(c.counter, ts.Counter)
+}
+
+// Tag identifies a single addition of an element to an ORSet.
+type Tag struct {
+	Replica string `json:"replica"`
+	Seq     uint64 `json:"seq"`
+}
+
+// newSet returns a new empty set with the default parameters.
+func newSet[T any]() *set.Set[T] {
+	return set.NewSet[T](8, hashmap.DefaultThreshold)
+}
+
+// newMap returns a new empty Map with the default parameters.
+func newMap[K, V any]() *hashmap.Map[K, V] {
+	return hashmap.NewMap[K, V](8, hashmap.DefaultThreshold)
+}
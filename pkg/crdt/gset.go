@@ -0,0 +1,80 @@
+package crdt
+
+import (
+	"encoding/json"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"iter"
+)
+
+// GSet represents a grow-only set: elements can be added, but never removed.
+// Merging takes the union of the replicas.
+type GSet[T any] struct {
+	elements *set.Set[T]
+	// delta holds the elements added since the last call to Delta
+	delta *set.Set[T]
+}
+
+// NewGSet returns a new empty GSet.
+func NewGSet[T any]() *GSet[T] {
+	return &GSet[T]{elements: newSet[T](), delta: newSet[T]()}
+}
+
+// Add adds values to the set.
+func (g *GSet[T]) Add(values ...T) {
+	for _, v := range values {
+		if !g.elements.Contains(v) {
+			g.elements.Add(v)
+			g.delta.Add(v)
+		}
+	}
+}
+
+// Contains checks if the set contains a value.
+func (g *GSet[T]) Contains(value T) bool {
+	return g.elements.Contains(value)
+}
+
+// Len returns the number of values in the set.
+func (g *GSet[T]) Len() int {
+	return g.elements.Len()
+}
+
+// Elements returns an iterator over the values in the set.
+func (g *GSet[T]) Elements() iter.Seq[T] {
+	return g.elements.Elements()
+}
+
+// Value returns a copy of the values in the set.
+func (g *GSet[T]) Value() *set.Set[T] {
+	return g.elements.Copy()
+}
+
+// Merge merges the state of another replica into the replica.
+func (g *GSet[T]) Merge(other *GSet[T]) {
+	g.elements.UnionWith(other.elements)
+}
+
+// Delta returns the values added since the last call to Delta.
+func (g *GSet[T]) Delta() *GSet[T] {
+	result := &GSet[T]{elements: g.delta, delta: newSet[T]()}
+	g.delta = newSet[T]()
+	return result
+}
+
+// MarshalJSON encodes the set as a JSON array of its values.
+func (g *GSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(g.elements.ToSlice())
+}
+
+// UnmarshalJSON decodes a JSON array of values, replacing the state of the replica.
+func (g *GSet[T]) UnmarshalJSON(data []byte) error {
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	*g = *NewGSet[T]()
+	g.elements.Add(values...)
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package crdt_test
+
+import (
+	"encoding/json"
+	"github.com/pietroagazzi/gohashlib/pkg/crdt"
+	"testing"
+)
+
+// roundTrip encodes a replica to JSON and decodes it into into.
+func roundTrip[T any](t *testing.T, replica T, into T) T {
+	t.Helper()
+
+	data, err := json.Marshal(replica)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, into); err != nil {
+		t.Fatal(err)
+	}
+
+	return into
+}
+
+func TestGSet_Merge(t *testing.T) {
+	a, b := crdt.NewGSet[string](), crdt.NewGSet[string]()
+
+	a.Add("x", "y")
+	b.Add("y", "z")
+
+	a.Merge(b)
+	b.Merge(a)
+
+	if a.Len() != 3 || !a.Value().Equal(b.Value()) {
+		t.Errorf("Expected replicas to converge to {x y z}, got %v and %v", a.Value(), b.Value())
+	}
+}
+
+func TestGSet_Delta(t *testing.T) {
+	a, b := crdt.NewGSet[string](), crdt.NewGSet[string]()
+
+	a.Add("x")
+	b.Merge(a.Delta())
+
+	a.Add("y")
+	delta := a.Delta()
+
+	if delta.Len() != 1 || !delta.Contains("y") {
+		t.Errorf("Expected delta {y}, got %v", delta.Value())
+	}
+
+	b.Merge(delta)
+	if !b.Value().Equal(a.Value()) {
+		t.Errorf("Expected %v, got %v", a.Value(), b.Value())
+	}
+}
+
+func TestGSet_MarshalJSON(t *testing.T) {
+	a := crdt.NewGSet[event]()
+	a.Add(event{1, "login"}, event{2, "logout"})
+
+	b := roundTrip(t, a, &crdt.GSet[event]{})
+
+	if !b.Value().Equal(a.Value()) {
+		t.Errorf("Expected %v, got %v", a.Value(), b.Value())
+	}
+}
@@ -0,0 +1,169 @@
+package crdt
+
+import (
+	"cmp"
+	"encoding/json"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"iter"
+)
+
+// LWWMap represents a last-writer-wins map: the value of a key is the value of its last
+// assignment, unless the key was deleted later. Operations are ordered by Timestamp.
+//
+// Deleted keys are remembered as tombstones, so that merging a replica that has not seen
+// the deletion yet does not set the key again.
+type LWWMap[K, V any] struct {
+	clock   clock
+	entries *hashmap.Map[K, lwwEntry[V]]
+	// delta holds the operations made since the last call to Delta
+	delta *LWWMap[K, V]
+}
+
+// lwwEntry is the last operation on a key of an LWWMap.
+type lwwEntry[V any] struct {
+	Value     V         `json:"value"`
+	Timestamp Timestamp `json:"timestamp"`
+	Deleted   bool      `json:"deleted,omitempty"`
+}
+
+// lwwMapJSON is the JSON encoding of an LWWMap.
+type lwwMapJSON[K, V any] struct {
+	Replica string              `json:"replica"`
+	Counter uint64              `json:"counter"`
+	Entries []lwwMapEntry[K, V] `json:"entries"`
+}
+
+// lwwMapEntry is the JSON encoding of a key of an LWWMap, with its last operation.
+type lwwMapEntry[K, V any] struct {
+	Key K `json:"key"`
+	lwwEntry[V]
+}
+
+// NewLWWMap returns a new empty LWWMap for a replica.
+// The name of the replica must be unique among the replicas.
+func NewLWWMap[K, V any](replica string) *LWWMap[K, V] {
+	m := newLWWMapState[K, V](replica)
+	m.delta = newLWWMapState[K, V]("")
+	return m
+}
+
+// newLWWMapState returns a new empty LWWMap without a delta.
+func newLWWMapState[K, V any](replica string) *LWWMap[K, V] {
+	return &LWWMap[K, V]{clock: clock{replica: replica}, entries: newMap[K, lwwEntry[V]]()}
+}
+
+// Replica returns the name of the replica.
+func (m *LWWMap[K, V]) Replica() string { return m.clock.replica }
+
+// apply records an operation on a key, unless a later one is recorded.
+func (m *LWWMap[K, V]) apply(key K, e lwwEntry[V]) {
+	if current, ok := m.entries.Get(key); !ok || e.Timestamp.After(current.Timestamp) {
+		m.entries.Set(key, e)
+	}
+}
+
+// record records a new operation of the replica on a key.
+func (m *LWWMap[K, V]) record(key K, e lwwEntry[V]) {
+	m.entries.Set(key, e)
+	if m.delta != nil {
+		m.delta.entries.Set(key, e)
+	}
+}
+
+// Get returns the value associated with the key.
+func (m *LWWMap[K, V]) Get(key K) (value V, ok bool) {
+	e, ok := m.entries.Get(key)
+	if !ok || e.Deleted {
+		return value, false
+	}
+	return e.Value, true
+}
+
+// Set associates the value with the key, replacing any previous value.
+func (m *LWWMap[K, V]) Set(key K, value V) {
+	m.record(key, lwwEntry[V]{Value: value, Timestamp: m.clock.tick()})
+}
+
+// Delete removes the key and its value.
+func (m *LWWMap[K, V]) Delete(key K) {
+	// Deleting a key that is not in the map would only add a tombstone
+	if _, ok := m.Get(key); !ok {
+		return
+	}
+
+	m.record(key, lwwEntry[V]{Timestamp: m.clock.tick(), Deleted: true})
+}
+
+// Len returns the number of items.
+func (m *LWWMap[K, V]) Len() int {
+	n := 0
+	for range m.All() {
+		n++
+	}
+	return n
+}
+
+// All returns an iterator over all items.
+func (m *LWWMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for key, e := range m.entries.All() {
+			if !e.Deleted && !yield(key, e.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Value returns a copy of the items of the map.
+func (m *LWWMap[K, V]) Value() *hashmap.Map[K, V] {
+	result := newMap[K, V]()
+	for key, value := range m.All() {
+		result.Set(key, value)
+	}
+	return result
+}
+
+// Merge merges the state of another replica into the replica,
+// keeping the last operation on each key.
+func (m *LWWMap[K, V]) Merge(other *LWWMap[K, V]) {
+	for key, e := range other.entries.All() {
+		m.apply(key, e)
+		m.clock.observe(e.Timestamp)
+	}
+}
+
+// Delta returns the operations made since the last call to Delta.
+func (m *LWWMap[K, V]) Delta() *LWWMap[K, V] {
+	// The changes of a delta state are not tracked until its first call to Delta
+	result := cmp.Or(m.delta, newLWWMapState[K, V](""))
+	m.delta = newLWWMapState[K, V]("")
+	return result
+}
+
+// MarshalJSON encodes the replica and the last operation on each key.
+func (m *LWWMap[K, V]) MarshalJSON() ([]byte, error) {
+	encoded := lwwMapJSON[K, V]{Replica: m.clock.replica, Counter: m.clock.counter, Entries: []lwwMapEntry[K, V]{}}
+
+	for key, e := range m.entries.All() {
+		encoded.Entries = append(encoded.Entries, lwwMapEntry[K, V]{Key: key, lwwEntry: e})
+	}
+
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON decodes the encoding of MarshalJSON, replacing the state of the replica.
+func (m *LWWMap[K, V]) UnmarshalJSON(data []byte) error {
+	var decoded lwwMapJSON[K, V]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*m = *NewLWWMap[K, V](decoded.Replica)
+	m.clock.counter = decoded.Counter
+
+	for _, e := range decoded.Entries {
+		m.apply(e.Key, e.lwwEntry)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package crdt_test
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/crdt"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap/hashmaptest"
+	"testing"
+)
+
+func TestLWWMap(t *testing.T) {
+	hashmaptest.Run(t, func() hashmap.Interface[int, int] { return crdt.NewLWWMap[int, int]("a") }, hashmaptest.Ints, hashmaptest.Ints)
+}
+
+func TestLWWMap_Merge(t *testing.T) {
+	a, b := crdt.NewLWWMap[string, int]("a"), crdt.NewLWWMap[string, int]("b")
+
+	a.Set("x", 1)
+	a.Set("y", 2)
+	b.Merge(a)
+
+	b.Set("x", 10)
+	b.Delete("y")
+	a.Merge(b)
+
+	if v, _ := a.Get("x"); v != 10 {
+		t.Errorf("Expected x to be 10, got %d", v)
+	}
+	if _, ok := a.Get("y"); ok {
+		t.Errorf("Expected y to be deleted")
+	}
+
+	// Merging an older state does not set y again
+	old := crdt.NewLWWMap[string, int]("c")
+	old.Set("y", 3)
+	b.Merge(old)
+
+	if v, ok := b.Get("y"); ok {
+		t.Errorf("Expected y to stay deleted, got %d", v)
+	}
+}
+
+func TestLWWMap_DeltaAndJSON(t *testing.T) {
+	a, b := crdt.NewLWWMap[event, string]("a"), crdt.NewLWWMap[event, string]("b")
+
+	a.Set(event{1, "login"}, "ok")
+	a.Set(event{2, "login"}, "denied")
+	b.Merge(a.Delta())
+
+	a.Delete(event{1, "login"})
+	delta := a.Delta()
+
+	// The delta only holds the deletion
+	if delta.Len() != 0 {
+		t.Errorf("Expected empty delta map, got %d items", delta.Len())
+	}
+
+	b.Merge(roundTrip(t, delta, &crdt.LWWMap[event, string]{}))
+
+	if !b.Value().Equal(a.Value()) || b.Len() != 1 {
+		t.Errorf("Expected %v, got %v", a.Value(), b.Value())
+	}
+}
@@ -0,0 +1,187 @@
+package crdt
+
+import (
+	"cmp"
+	"encoding/json"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"iter"
+)
+
+// LWWSet represents a last-writer-wins element set: elements can be added and removed any
+// number of times, and an element is in the set if its last addition is later than its last
+// removal. Operations are ordered by Timestamp.
+type LWWSet[T any] struct {
+	clock clock
+	// adds and removes map each element to the timestamp of its last addition and removal
+	adds    *hashmap.Map[T, Timestamp]
+	removes *hashmap.Map[T, Timestamp]
+	// delta holds the operations made since the last call to Delta
+	delta *LWWSet[T]
+}
+
+// lwwSetJSON is the JSON encoding of an LWWSet.
+type lwwSetJSON[T any] struct {
+	Replica string           `json:"replica"`
+	Counter uint64           `json:"counter"`
+	Adds    []lwwSetEntry[T] `json:"adds"`
+	Removes []lwwSetEntry[T] `json:"removes"`
+}
+
+// lwwSetEntry is the JSON encoding of an operation of an LWWSet.
+type lwwSetEntry[T any] struct {
+	Value     T         `json:"value"`
+	Timestamp Timestamp `json:"timestamp"`
+}
+
+// NewLWWSet returns a new empty LWWSet for a replica.
+// The name of the replica must be unique among the replicas.
+func NewLWWSet[T any](replica string) *LWWSet[T] {
+	s := newLWWSetState[T](replica)
+	s.delta = newLWWSetState[T]("")
+	return s
+}
+
+// newLWWSetState returns a new empty LWWSet without a delta.
+func newLWWSetState[T any](replica string) *LWWSet[T] {
+	return &LWWSet[T]{
+		clock:   clock{replica: replica},
+		adds:    newMap[T, Timestamp](),
+		removes: newMap[T, Timestamp](),
+	}
+}
+
+// Replica returns the name of the replica.
+func (s *LWWSet[T]) Replica() string { return s.clock.replica }
+
+// setLater records the timestamp of an operation on an element, unless a later one is recorded.
+func setLater[K any](m *hashmap.Map[K, Timestamp], key K, ts Timestamp) {
+	if current, ok := m.Get(key); !ok || ts.After(current) {
+		m.Set(key, ts)
+	}
+}
+
+// Add adds values to the set.
+func (s *LWWSet[T]) Add(values ...T) {
+	for _, v := range values {
+		ts := s.clock.tick()
+
+		s.adds.Set(v, ts)
+		if s.delta != nil {
+			s.delta.adds.Set(v, ts)
+		}
+	}
+}
+
+// Remove removes a value from the set.
+func (s *LWWSet[T]) Remove(value T) {
+	// Removing a value that is not in the set would only add a tombstone
+	if !s.Contains(value) {
+		return
+	}
+
+	ts := s.clock.tick()
+
+	s.removes.Set(value, ts)
+	if s.delta != nil {
+		s.delta.removes.Set(value, ts)
+	}
+}
+
+// Contains checks if the set contains a value.
+func (s *LWWSet[T]) Contains(value T) bool {
+	added, ok := s.adds.Get(value)
+	if !ok {
+		return false
+	}
+
+	removed, ok := s.removes.Get(value)
+	return !ok || added.After(removed)
+}
+
+// Len returns the number of values in the set.
+func (s *LWWSet[T]) Len() int {
+	n := 0
+	for range s.Elements() {
+		n++
+	}
+	return n
+}
+
+// Elements returns an iterator over the values in the set.
+func (s *LWWSet[T]) Elements() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for value, added := range s.adds.All() {
+			if removed, ok := s.removes.Get(value); ok && !added.After(removed) {
+				continue
+			}
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Value returns a copy of the values in the set.
+func (s *LWWSet[T]) Value() *set.Set[T] {
+	result := newSet[T]()
+	for value := range s.Elements() {
+		result.Add(value)
+	}
+	return result
+}
+
+// Merge merges the state of another replica into the replica,
+// keeping the last addition and removal of each element.
+func (s *LWWSet[T]) Merge(other *LWWSet[T]) {
+	for value, ts := range other.adds.All() {
+		setLater(s.adds, value, ts)
+		s.clock.observe(ts)
+	}
+	for value, ts := range other.removes.All() {
+		setLater(s.removes, value, ts)
+		s.clock.observe(ts)
+	}
+}
+
+// Delta returns the operations made since the last call to Delta.
+func (s *LWWSet[T]) Delta() *LWWSet[T] {
+	// The changes of a delta state are not tracked until its first call to Delta
+	result := cmp.Or(s.delta, newLWWSetState[T](""))
+	s.delta = newLWWSetState[T]("")
+	return result
+}
+
+// MarshalJSON encodes the replica and the last addition and removal of each element.
+func (s *LWWSet[T]) MarshalJSON() ([]byte, error) {
+	encoded := lwwSetJSON[T]{Replica: s.clock.replica, Counter: s.clock.counter, Adds: []lwwSetEntry[T]{}, Removes: []lwwSetEntry[T]{}}
+
+	for value, ts := range s.adds.All() {
+		encoded.Adds = append(encoded.Adds, lwwSetEntry[T]{Value: value, Timestamp: ts})
+	}
+	for value, ts := range s.removes.All() {
+		encoded.Removes = append(encoded.Removes, lwwSetEntry[T]{Value: value, Timestamp: ts})
+	}
+
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON decodes the encoding of MarshalJSON, replacing the state of the replica.
+func (s *LWWSet[T]) UnmarshalJSON(data []byte) error {
+	var decoded lwwSetJSON[T]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*s = *NewLWWSet[T](decoded.Replica)
+	s.clock.counter = decoded.Counter
+
+	for _, e := range decoded.Adds {
+		setLater(s.adds, e.Value, e.Timestamp)
+	}
+	for _, e := range decoded.Removes {
+		setLater(s.removes, e.Value, e.Timestamp)
+	}
+
+	return nil
+}
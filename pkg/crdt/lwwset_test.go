@@ -0,0 +1,70 @@
+package crdt_test
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/crdt"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"github.com/pietroagazzi/gohashlib/pkg/set/settest"
+	"testing"
+)
+
+func TestLWWSet(t *testing.T) {
+	settest.Run(t, func() set.Interface[int] { return crdt.NewLWWSet[int]("a") }, settest.Ints)
+}
+
+func TestLWWSet_LastWriterWins(t *testing.T) {
+	a, b := crdt.NewLWWSet[string]("a"), crdt.NewLWWSet[string]("b")
+
+	a.Add("x")
+	b.Merge(a)
+
+	// b observed the addition, so its removal is later
+	b.Remove("x")
+	a.Merge(b)
+
+	if a.Contains("x") {
+		t.Errorf("Expected x to be removed, got %v", a.Value())
+	}
+
+	// Concurrent operations with equal counters are ordered by replica
+	a.Add("y")
+	b.Add("y")
+	b.Remove("y")
+	a.Merge(b)
+	b.Merge(a)
+
+	if a.Contains("y") != b.Contains("y") {
+		t.Errorf("Expected replicas to agree on y, got %v and %v", a.Value(), b.Value())
+	}
+}
+
+func TestLWWSet_Delta(t *testing.T) {
+	a, b := crdt.NewLWWSet[int]("a"), crdt.NewLWWSet[int]("b")
+
+	a.Add(1, 2, 3)
+	b.Merge(a.Delta())
+
+	a.Remove(2)
+	b.Merge(a.Delta())
+
+	if !b.Value().Equal(a.Value()) || b.Len() != 2 {
+		t.Errorf("Expected %v, got %v", a.Value(), b.Value())
+	}
+}
+
+func TestLWWSet_MarshalJSON(t *testing.T) {
+	a := crdt.NewLWWSet[event]("a")
+	a.Add(event{1, "login"}, event{2, "logout"})
+	a.Remove(event{1, "login"})
+
+	b := roundTrip(t, a, &crdt.LWWSet[event]{})
+
+	if b.Replica() != "a" || !b.Value().Equal(a.Value()) {
+		t.Errorf("Expected %v, got %v", a.Value(), b.Value())
+	}
+
+	// The restored clock orders new operations after the old ones
+	b.Add(event{1, "login"})
+	if !b.Contains(event{1, "login"}) {
+		t.Errorf("Expected {1 login} to be added again")
+	}
+}
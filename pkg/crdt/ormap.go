@@ -0,0 +1,180 @@
+package crdt
+
+import (
+	"cmp"
+	"encoding/json"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"iter"
+)
+
+// ORMap represents an observed-remove map whose values are themselves CRDTs, such as a map
+// from users to their GSet of permissions. Keys are kept in an ORSet, and the values of a key
+// on different replicas are merged with their own Merge.
+//
+// Updating a key adds it again to the ORSet of keys, so an update concurrent with a removal
+// wins, and the key keeps the merged values of the replicas, including those from before the
+// removal. To that end, removing a key only hides its value: values are never dropped, and
+// a key updated again after its removal keeps its former value too.
+type ORMap[K any, V Mergeable[V]] struct {
+	keys *ORSet[K]
+	// values holds the values of the keys, including the keys removed
+	values *hashmap.Map[K, V]
+	// newValue returns the value of a key before its first update
+	newValue func() V
+	// updated holds the keys updated since the last call to Delta
+	updated *set.Set[K]
+}
+
+// orMapJSON is the JSON encoding of an ORMap.
+type orMapJSON[K, V any] struct {
+	Keys   *ORSet[K]          `json:"keys"`
+	Values []orMapEntry[K, V] `json:"values"`
+}
+
+// orMapEntry is the JSON encoding of a key of an ORMap, with its value.
+type orMapEntry[K, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+// NewORMap returns a new empty ORMap for a replica, whose values start as returned by newValue.
+// The name of the replica must be unique among the replicas.
+func NewORMap[K any, V Mergeable[V]](replica string, newValue func() V) *ORMap[K, V] {
+	return &ORMap[K, V]{
+		keys:     NewORSet[K](replica),
+		values:   newMap[K, V](),
+		newValue: newValue,
+		updated:  newSet[K](),
+	}
+}
+
+// Replica returns the name of the replica.
+func (m *ORMap[K, V]) Replica() string { return m.keys.Replica() }
+
+// value returns the value of a key, or a new value if the key has never been updated.
+// The new value is not added to the map.
+func (m *ORMap[K, V]) value(key K) V {
+	if v, ok := m.values.Get(key); ok {
+		return v
+	}
+	return m.newValue()
+}
+
+// Update calls f with the value of a key, to modify it, adding the key if it is not in the map.
+func (m *ORMap[K, V]) Update(key K, f func(V)) {
+	v, ok := m.values.Get(key)
+	if !ok {
+		v = m.newValue()
+		m.values.Set(key, v)
+	}
+
+	m.keys.Add(key)
+	f(v)
+	m.updated.Add(key)
+}
+
+// Get returns the value of a key.
+// The value must only be modified through Update.
+func (m *ORMap[K, V]) Get(key K) (value V, ok bool) {
+	if !m.keys.Contains(key) {
+		return value, false
+	}
+	return m.value(key), true
+}
+
+// Remove removes a key. Its value is kept, to be merged if the key is updated again.
+func (m *ORMap[K, V]) Remove(key K) {
+	m.keys.Remove(key)
+}
+
+// Contains checks if the map contains a key.
+func (m *ORMap[K, V]) Contains(key K) bool {
+	return m.keys.Contains(key)
+}
+
+// Len returns the number of keys.
+func (m *ORMap[K, V]) Len() int {
+	return m.keys.Len()
+}
+
+// All returns an iterator over the keys and their values.
+// The values must only be modified through Update.
+func (m *ORMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for key := range m.keys.Elements() {
+			if !yield(key, m.value(key)) {
+				return
+			}
+		}
+	}
+}
+
+// Merge merges the state of another replica into the replica: the keys are merged as an ORSet,
+// and the values with their own Merge, whether their keys were removed or not.
+//
+// Merging the values of removed keys too makes a key re-added by a delta hold the same value
+// on every replica, even on those that removed it before receiving the whole value.
+func (m *ORMap[K, V]) Merge(other *ORMap[K, V]) {
+	m.keys.Merge(other.keys)
+
+	for key, v := range other.values.All() {
+		current, ok := m.values.Get(key)
+		if !ok {
+			current = m.newValue()
+			m.values.Set(key, current)
+		}
+		current.Merge(v)
+	}
+}
+
+// Delta returns the keys added and removed since the last call to Delta,
+// along with the deltas of the values updated.
+func (m *ORMap[K, V]) Delta() *ORMap[K, V] {
+	result := &ORMap[K, V]{
+		keys:     m.keys.Delta(),
+		values:   newMap[K, V](),
+		newValue: m.newValue,
+		updated:  newSet[K](),
+	}
+
+	for key := range m.updated.Elements() {
+		if v, ok := m.values.Get(key); ok {
+			result.values.Set(key, v.Delta())
+		}
+	}
+	m.updated.Clear()
+
+	return result
+}
+
+// MarshalJSON encodes the keys as an ORSet, and the values with their own encoding.
+func (m *ORMap[K, V]) MarshalJSON() ([]byte, error) {
+	encoded := orMapJSON[K, V]{Keys: m.keys, Values: []orMapEntry[K, V]{}}
+
+	for key, v := range m.values.All() {
+		encoded.Values = append(encoded.Values, orMapEntry[K, V]{Key: key, Value: v})
+	}
+
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON decodes the encoding of MarshalJSON, replacing the state of the replica.
+// The map must have been created with NewORMap, to create the values of new keys.
+func (m *ORMap[K, V]) UnmarshalJSON(data []byte) error {
+	decoded := orMapJSON[K, V]{Keys: NewORSet[K]("")}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	// A null set decodes to nil
+	m.keys = cmp.Or(decoded.Keys, NewORSet[K](""))
+	m.values = newMap[K, V]()
+	m.updated = newSet[K]()
+
+	for _, e := range decoded.Values {
+		m.values.Set(e.Key, e.Value)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package crdt_test
+
+import (
+	"encoding/json"
+	"github.com/pietroagazzi/gohashlib/pkg/crdt"
+	"strings"
+	"testing"
+)
+
+// newPermissions returns an ORMap of users to their sets of permissions.
+func newPermissions(replica string) *crdt.ORMap[string, *crdt.GSet[string]] {
+	return crdt.NewORMap[string](replica, crdt.NewGSet[string])
+}
+
+// grant adds permissions to a user.
+func grant(m *crdt.ORMap[string, *crdt.GSet[string]], user string, permissions ...string) {
+	m.Update(user, func(s *crdt.GSet[string]) { s.Add(permissions...) })
+}
+
+func TestORMap_Merge(t *testing.T) {
+	a, b := newPermissions("a"), newPermissions("b")
+
+	grant(a, "alice", "read")
+	grant(b, "alice", "write")
+	grant(b, "bob", "read")
+
+	a.Merge(b)
+	b.Merge(a)
+
+	for _, m := range []*crdt.ORMap[string, *crdt.GSet[string]]{a, b} {
+		alice, ok := m.Get("alice")
+		if !ok || alice.Len() != 2 || m.Len() != 2 {
+			t.Errorf("Expected alice to read and write, and bob, got %v", alice.Value())
+		}
+	}
+
+	// The removal of bob applies to the other replica
+	a.Remove("bob")
+	b.Merge(a)
+
+	if b.Contains("bob") {
+		t.Errorf("Expected bob to be removed")
+	}
+
+	// An update concurrent with a removal wins
+	a.Remove("alice")
+	grant(b, "alice", "admin")
+	a.Merge(b)
+
+	if alice, ok := a.Get("alice"); !ok || !alice.Contains("admin") {
+		t.Errorf("Expected alice to be kept with admin")
+	}
+}
+
+func TestORMap_Delta(t *testing.T) {
+	a, b := newPermissions("a"), newPermissions("b")
+
+	grant(a, "alice", "read")
+	b.Merge(a.Delta())
+
+	grant(a, "alice", "write")
+	delta := a.Delta()
+
+	// The delta only holds the new permission
+	if alice, _ := delta.Get("alice"); alice.Len() != 1 || !alice.Contains("write") {
+		t.Errorf("Expected delta {write}, got %v", alice.Value())
+	}
+
+	b.Merge(delta)
+	if alice, _ := b.Get("alice"); alice.Len() != 2 {
+		t.Errorf("Expected alice to read and write, got %v", alice.Value())
+	}
+}
+
+func TestORMap_MarshalJSON(t *testing.T) {
+	a := newPermissions("a")
+	grant(a, "alice", "read", "write")
+	grant(a, "bob", "read")
+	a.Remove("bob")
+
+	b := roundTrip(t, a, newPermissions(""))
+
+	if b.Replica() != "a" || b.Len() != 1 || b.Contains("bob") {
+		t.Errorf("Expected only alice, got %d keys", b.Len())
+	}
+	if alice, _ := b.Get("alice"); alice.Len() != 2 {
+		t.Errorf("Expected alice to read and write, got %v", alice.Value())
+	}
+
+	grant(b, "carol", "read")
+	if carol, ok := b.Get("carol"); !ok || carol.Len() != 1 {
+		t.Errorf("Expected carol to be added")
+	}
+}
+
+func TestORMap_DeltaConcurrentRemove(t *testing.T) {
+	a, b := newPermissions("a"), newPermissions("b")
+
+	grant(a, "alice", "read")
+	b.Merge(a.Delta())
+
+	// a removes alice while b grants her a new permission
+	a.Remove("alice")
+	grant(b, "alice", "write")
+
+	fromA, fromB := a.Delta(), b.Delta()
+	a.Merge(fromB)
+	b.Merge(fromA)
+
+	for _, m := range []*crdt.ORMap[string, *crdt.GSet[string]]{a, b} {
+		alice, ok := m.Get("alice")
+		if !ok || alice.Len() != 2 || !alice.Contains("read") {
+			t.Errorf("Expected alice to read and write on %s, got %v", m.Replica(), alice.Value())
+		}
+	}
+}
+
+func TestORMap_Get(t *testing.T) {
+	// A key without a value reads as a new value
+	data := `{"keys":{"replica":"a","seq":1,"entries":[{"value":"alice","tags":[{"replica":"a","seq":1}]}],"tombstones":[]},"values":[]}`
+
+	m := newPermissions("")
+	if err := json.Unmarshal([]byte(data), m); err != nil {
+		t.Fatal(err)
+	}
+
+	if alice, ok := m.Get("alice"); !ok || alice.Len() != 0 {
+		t.Errorf("Expected alice without permissions")
+	}
+	for range m.All() {
+	}
+
+	// Reading does not change the state
+	if encoded, _ := json.Marshal(m); strings.Contains(string(encoded), `"key"`) {
+		t.Errorf("Expected no values, got %s", encoded)
+	}
+}
@@ -0,0 +1,198 @@
+package crdt
+
+import (
+	"cmp"
+	"encoding/json"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"iter"
+)
+
+// ORSet represents an observed-remove set: elements can be added and removed any number
+// of times. Each addition is identified by a unique tag, and a removal only removes the tags
+// its replica has observed, so an addition concurrent with a removal wins.
+//
+// Removed tags are remembered as tombstones, so that merging a replica that has not seen
+// the removal yet does not add the element back.
+type ORSet[T any] struct {
+	replica string
+	// seq is the sequence number of the last tag of the replica
+	seq uint64
+	// entries maps each element to the tags of its additions that were not removed
+	entries *hashmap.Map[T, *set.Set[Tag]]
+	// tombstones holds the tags of the additions that were removed
+	tombstones *set.Set[Tag]
+	// delta holds the tags added and removed since the last call to Delta
+	delta *ORSet[T]
+}
+
+// orSetJSON is the JSON encoding of an ORSet.
+type orSetJSON[T any] struct {
+	Replica    string          `json:"replica"`
+	Seq        uint64          `json:"seq"`
+	Entries    []orSetEntry[T] `json:"entries"`
+	Tombstones []Tag           `json:"tombstones"`
+}
+
+// orSetEntry is the JSON encoding of an element of an ORSet, with its tags.
+type orSetEntry[T any] struct {
+	Value T     `json:"value"`
+	Tags  []Tag `json:"tags"`
+}
+
+// NewORSet returns a new empty ORSet for a replica.
+// The name of the replica must be unique among the replicas.
+func NewORSet[T any](replica string) *ORSet[T] {
+	s := newORSetState[T](replica)
+	s.delta = newORSetState[T]("")
+	return s
+}
+
+// newORSetState returns a new empty ORSet without a delta.
+func newORSetState[T any](replica string) *ORSet[T] {
+	return &ORSet[T]{replica: replica, entries: newMap[T, *set.Set[Tag]](), tombstones: newSet[Tag]()}
+}
+
+// Replica returns the name of the replica.
+func (s *ORSet[T]) Replica() string { return s.replica }
+
+// addTag adds a tag to the tags of an element.
+func (s *ORSet[T]) addTag(value T, tag Tag) {
+	tags, ok := s.entries.Get(value)
+	if !ok {
+		tags = newSet[Tag]()
+		s.entries.Set(value, tags)
+	}
+	tags.Add(tag)
+}
+
+// Add adds values to the set, each with a new tag.
+func (s *ORSet[T]) Add(values ...T) {
+	for _, v := range values {
+		s.seq++
+		tag := Tag{Replica: s.replica, Seq: s.seq}
+
+		s.addTag(v, tag)
+		if s.delta != nil {
+			s.delta.addTag(v, tag)
+		}
+	}
+}
+
+// Remove removes a value from the set, along with all the tags of its additions observed.
+func (s *ORSet[T]) Remove(value T) {
+	tags, ok := s.entries.Get(value)
+	if !ok {
+		return
+	}
+
+	s.tombstones.UnionWith(tags)
+	if s.delta != nil {
+		s.delta.tombstones.UnionWith(tags)
+	}
+	s.entries.Delete(value)
+}
+
+// Contains checks if the set contains a value.
+func (s *ORSet[T]) Contains(value T) bool {
+	_, ok := s.entries.Get(value)
+	return ok
+}
+
+// Len returns the number of values in the set.
+func (s *ORSet[T]) Len() int {
+	return s.entries.Len()
+}
+
+// Elements returns an iterator over the values in the set.
+func (s *ORSet[T]) Elements() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for value := range s.entries.All() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Value returns a copy of the values in the set.
+func (s *ORSet[T]) Value() *set.Set[T] {
+	result := newSet[T]()
+	for value := range s.Elements() {
+		result.Add(value)
+	}
+	return result
+}
+
+// Merge merges the state of another replica into the replica:
+// the tags of both replicas, minus the tags removed by either.
+func (s *ORSet[T]) Merge(other *ORSet[T]) {
+	s.tombstones.UnionWith(other.tombstones)
+
+	for value, tags := range other.entries.All() {
+		for tag := range tags.Elements() {
+			if !s.tombstones.Contains(tag) {
+				s.addTag(value, tag)
+			}
+
+			// Never reuse a tag of the replica, e.g. after restoring an older state
+			if tag.Replica == s.replica {
+				s.seq = max(s.seq, tag.Seq)
+			}
+		}
+	}
+
+	if other.tombstones.Len() == 0 {
+		return
+	}
+
+	var emptied []T
+	for value, tags := range s.entries.All() {
+		tags.DifferenceWith(other.tombstones)
+		if tags.Len() == 0 {
+			emptied = append(emptied, value)
+		}
+	}
+	for _, value := range emptied {
+		s.entries.Delete(value)
+	}
+}
+
+// Delta returns the tags added and removed since the last call to Delta.
+func (s *ORSet[T]) Delta() *ORSet[T] {
+	// The changes of a delta state are not tracked until its first call to Delta
+	result := cmp.Or(s.delta, newORSetState[T](""))
+	s.delta = newORSetState[T]("")
+	return result
+}
+
+// MarshalJSON encodes the replica, its elements with their tags, and the tombstones.
+func (s *ORSet[T]) MarshalJSON() ([]byte, error) {
+	encoded := orSetJSON[T]{Replica: s.replica, Seq: s.seq, Entries: []orSetEntry[T]{}, Tombstones: s.tombstones.ToSlice()}
+
+	for value, tags := range s.entries.All() {
+		encoded.Entries = append(encoded.Entries, orSetEntry[T]{Value: value, Tags: tags.ToSlice()})
+	}
+
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON decodes the encoding of MarshalJSON, replacing the state of the replica.
+func (s *ORSet[T]) UnmarshalJSON(data []byte) error {
+	var decoded orSetJSON[T]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*s = *NewORSet[T](decoded.Replica)
+	s.seq = decoded.Seq
+	s.tombstones.Add(decoded.Tombstones...)
+
+	for _, e := range decoded.Entries {
+		for _, tag := range e.Tags {
+			s.addTag(e.Value, tag)
+		}
+	}
+
+	return nil
+}
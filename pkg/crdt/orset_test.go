@@ -0,0 +1,76 @@
+package crdt_test
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/crdt"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"github.com/pietroagazzi/gohashlib/pkg/set/settest"
+	"testing"
+)
+
+type event struct {
+	User int
+	Kind string
+}
+
+func TestORSet(t *testing.T) {
+	settest.Run(t, func() set.Interface[int] { return crdt.NewORSet[int]("a") }, settest.Ints)
+}
+
+func TestORSet_ConcurrentAddWins(t *testing.T) {
+	a, b := crdt.NewORSet[string]("a"), crdt.NewORSet[string]("b")
+
+	a.Add("x")
+	b.Merge(a)
+
+	// b removes the addition it observed, while a adds x again
+	b.Remove("x")
+	a.Add("x")
+
+	a.Merge(b)
+	b.Merge(a)
+
+	if !a.Contains("x") || !b.Contains("x") {
+		t.Errorf("Expected the concurrent addition to win, got %v and %v", a.Value(), b.Value())
+	}
+
+	// Once observed, the removal applies on both replicas
+	a.Remove("x")
+	b.Merge(a)
+
+	if b.Contains("x") {
+		t.Errorf("Expected x to be removed, got %v", b.Value())
+	}
+
+	// Merging an older state does not add it back
+	old := crdt.NewORSet[string]("c")
+	old.Add("y")
+	a.Merge(old)
+	a.Remove("y")
+	a.Merge(old)
+
+	if a.Contains("y") {
+		t.Errorf("Expected y to stay removed, got %v", a.Value())
+	}
+}
+
+func TestORSet_MarshalJSON(t *testing.T) {
+	a := crdt.NewORSet[event]("a")
+	a.Add(event{1, "login"}, event{2, "logout"})
+	a.Remove(event{2, "logout"})
+
+	b := roundTrip(t, a, &crdt.ORSet[event]{})
+
+	if b.Replica() != "a" || !b.Value().Equal(a.Value()) {
+		t.Errorf("Expected %v, got %v", a.Value(), b.Value())
+	}
+
+	// The restored replica does not reuse the tags of its additions
+	b.Add(event{3, "login"})
+	a.Merge(b)
+	a.Remove(event{1, "login"})
+	b.Merge(a)
+
+	if b.Contains(event{1, "login"}) || !b.Contains(event{3, "login"}) {
+		t.Errorf("Expected {3 login}, got %v", b.Value())
+	}
+}
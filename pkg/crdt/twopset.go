@@ -0,0 +1,102 @@
+package crdt
+
+import (
+	"cmp"
+	"encoding/json"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"iter"
+)
+
+// TwoPSet represents a two-phase set: elements can be added and removed, but once removed
+// an element can never be added again. Removed elements are remembered as tombstones.
+// Merging takes the union of the additions and of the removals of the replicas.
+type TwoPSet[T any] struct {
+	added   *GSet[T]
+	removed *GSet[T]
+}
+
+// twoPSetJSON is the JSON encoding of a TwoPSet.
+type twoPSetJSON[T any] struct {
+	Added   *GSet[T] `json:"added"`
+	Removed *GSet[T] `json:"removed"`
+}
+
+// NewTwoPSet returns a new empty TwoPSet.
+func NewTwoPSet[T any]() *TwoPSet[T] {
+	return &TwoPSet[T]{added: NewGSet[T](), removed: NewGSet[T]()}
+}
+
+// Add adds values to the set. Values that were removed are not added again.
+func (s *TwoPSet[T]) Add(values ...T) {
+	s.added.Add(values...)
+}
+
+// Remove removes a value from the set, for good.
+// Values that are not in the set are not removed, so they can still be added.
+func (s *TwoPSet[T]) Remove(value T) {
+	if s.added.Contains(value) {
+		s.removed.Add(value)
+	}
+}
+
+// Contains checks if the set contains a value.
+func (s *TwoPSet[T]) Contains(value T) bool {
+	return s.added.Contains(value) && !s.removed.Contains(value)
+}
+
+// Len returns the number of values in the set.
+func (s *TwoPSet[T]) Len() int {
+	n := 0
+	for range s.Elements() {
+		n++
+	}
+	return n
+}
+
+// Elements returns an iterator over the values in the set.
+func (s *TwoPSet[T]) Elements() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for value := range s.added.Elements() {
+			if !s.removed.Contains(value) && !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Value returns a copy of the values in the set.
+func (s *TwoPSet[T]) Value() *set.Set[T] {
+	result := newSet[T]()
+	for value := range s.Elements() {
+		result.Add(value)
+	}
+	return result
+}
+
+// Merge merges the state of another replica into the replica.
+func (s *TwoPSet[T]) Merge(other *TwoPSet[T]) {
+	s.added.Merge(other.added)
+	s.removed.Merge(other.removed)
+}
+
+// Delta returns the values added and removed since the last call to Delta.
+func (s *TwoPSet[T]) Delta() *TwoPSet[T] {
+	return &TwoPSet[T]{added: s.added.Delta(), removed: s.removed.Delta()}
+}
+
+// MarshalJSON encodes the values added and removed.
+func (s *TwoPSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(twoPSetJSON[T]{Added: s.added, Removed: s.removed})
+}
+
+// UnmarshalJSON decodes the values added and removed, replacing the state of the replica.
+func (s *TwoPSet[T]) UnmarshalJSON(data []byte) error {
+	decoded := twoPSetJSON[T]{Added: NewGSet[T](), Removed: NewGSet[T]()}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	// A null set decodes to nil
+	s.added, s.removed = cmp.Or(decoded.Added, NewGSet[T]()), cmp.Or(decoded.Removed, NewGSet[T]())
+	return nil
+}
@@ -0,0 +1,57 @@
+package crdt_test
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/crdt"
+	"testing"
+)
+
+func TestTwoPSet_Remove(t *testing.T) {
+	a, b := crdt.NewTwoPSet[int](), crdt.NewTwoPSet[int]()
+
+	a.Add(1, 2)
+	b.Merge(a)
+	b.Remove(1)
+
+	// A removed value cannot be added again
+	a.Add(1)
+	a.Merge(b)
+
+	if a.Contains(1) || !a.Contains(2) || a.Len() != 1 {
+		t.Errorf("Expected {2}, got %v", a.Value())
+	}
+
+	// Removing a value never added does not prevent adding it
+	a.Remove(3)
+	a.Add(3)
+
+	if !a.Contains(3) {
+		t.Errorf("Expected 3 to be added")
+	}
+}
+
+func TestTwoPSet_Delta(t *testing.T) {
+	a, b := crdt.NewTwoPSet[int](), crdt.NewTwoPSet[int]()
+
+	a.Add(1, 2)
+	b.Merge(a.Delta())
+
+	a.Remove(2)
+	b.Merge(a.Delta())
+
+	if !b.Value().Equal(a.Value()) || b.Len() != 1 {
+		t.Errorf("Expected %v, got %v", a.Value(), b.Value())
+	}
+}
+
+func TestTwoPSet_MarshalJSON(t *testing.T) {
+	a := crdt.NewTwoPSet[int]()
+	a.Add(1, 2)
+	a.Remove(1)
+
+	b := roundTrip(t, a, &crdt.TwoPSet[int]{})
+	b.Add(1)
+
+	if b.Contains(1) || !b.Contains(2) {
+		t.Errorf("Expected {2}, got %v", b.Value())
+	}
+}
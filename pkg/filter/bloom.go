@@ -0,0 +1,207 @@
+package filter
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/utils"
+	"math"
+	"math/bits"
+)
+
+// ErrIncompatible is returned when combining filters built with different parameters.
+var ErrIncompatible = errors.New("filter: incompatible parameters")
+
+// ErrInvalidEncoding is returned when decoding bytes that are not an encoded filter.
+var ErrInvalidEncoding = errors.New("filter: invalid encoding")
+
+// MaxHashFunctions is the largest number of positions set for each value in a filter,
+// enough for any false positive rate above 2^-64.
+const MaxHashFunctions = 64
+
+// BloomFilter represents a probabilistic set, answering whether a value might have been added.
+// https://en.wikipedia.org/wiki/Bloom_filter
+//
+// A value that was added is always reported, while a value that was not is reported with a
+// small false positive rate. Values are hashed from the same encoding as hashmap.Map keys,
+// and the k positions of a value are derived from two hashes by double hashing.
+type BloomFilter[T any] struct {
+	// bits is the bit array, as words of 64 bits
+	bits []uint64
+	// m is the number of bits
+	m uint64
+	// k is the number of positions set for each value
+	k uint64
+}
+
+// NewBloomFilter returns a new BloomFilter sized to hold the expected number of items
+// with the given false positive rate.
+func NewBloomFilter[T any](expectedItems uint, falsePositiveRate float64) *BloomFilter[T] {
+	m, k := OptimalParameters(expectedItems, falsePositiveRate)
+	return NewBloomFilterWithParameters[T](m, k)
+}
+
+// NewBloomFilterWithParameters returns a new BloomFilter with m bits and k hash functions,
+// at most MaxHashFunctions.
+func NewBloomFilterWithParameters[T any](m, k uint64) *BloomFilter[T] {
+	m, k = max(m, 1), min(max(k, 1), MaxHashFunctions)
+
+	return &BloomFilter[T]{
+		bits: make([]uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+	}
+}
+
+// OptimalParameters returns the number of bits m and of hash functions k minimizing the
+// memory of a filter holding n items with the false positive rate p.
+//
+// m = -n ln(p) / ln(2)^2 and k = m/n ln(2), at most MaxHashFunctions
+func OptimalParameters(n uint, p float64) (m, k uint64) {
+	n = max(n, 1)
+	p = min(max(p, math.SmallestNonzeroFloat64), 1)
+
+	m = uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	m = max(m, 1)
+	k = uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+
+	return m, min(max(k, 1), MaxHashFunctions)
+}
+
+// locations calls f with each of the k positions of a value.
+func locations(h1, h2, k, m uint64, f func(uint64)) {
+	for i := uint64(0); i < k; i++ {
+		f((h1 + i*h2) % m)
+	}
+}
+
+// Add adds a value to the filter.
+// It returns an error if the value cannot be encoded.
+func (bf *BloomFilter[T]) Add(value T) error {
+	h1, h2, err := utils.DoubleHash(value)
+	if err != nil {
+		return err
+	}
+
+	locations(h1, h2, bf.k, bf.m, func(i uint64) {
+		bf.bits[i/64] |= 1 << (i % 64)
+	})
+
+	return nil
+}
+
+// MightContain checks if a value might have been added to the filter.
+// It returns false if the value cannot be encoded, since it could not have been added.
+func (bf *BloomFilter[T]) MightContain(value T) bool {
+	h1, h2, err := utils.DoubleHash(value)
+	if err != nil {
+		return false
+	}
+
+	found := true
+	locations(h1, h2, bf.k, bf.m, func(i uint64) {
+		found = found && bf.bits[i/64]&(1<<(i%64)) != 0
+	})
+
+	return found
+}
+
+// Union adds to the filter all the values added to another filter.
+// Both filters must have the same number of bits and of hash functions.
+func (bf *BloomFilter[T]) Union(other *BloomFilter[T]) error {
+	if bf.m != other.m || bf.k != other.k {
+		return ErrIncompatible
+	}
+
+	for i := range bf.bits {
+		bf.bits[i] |= other.bits[i]
+	}
+
+	return nil
+}
+
+// EstimatedCount returns an estimate of the number of distinct values added to the filter,
+// from the number of bits set (Swamidass and Baldi).
+//
+// n = -m/k ln(1 - X/m), where X is the number of bits set
+func (bf *BloomFilter[T]) EstimatedCount() float64 {
+	x := float64(bf.onesCount())
+	m := float64(bf.m)
+
+	if x >= m {
+		return math.Inf(1)
+	}
+
+	return -m / float64(bf.k) * math.Log(1-x/m)
+}
+
+// FalsePositiveRate returns the expected false positive rate of the filter, given the
+// fraction of bits set.
+func (bf *BloomFilter[T]) FalsePositiveRate() float64 {
+	return math.Pow(float64(bf.onesCount())/float64(bf.m), float64(bf.k))
+}
+
+// onesCount returns the number of bits set.
+func (bf *BloomFilter[T]) onesCount() int {
+	count := 0
+	for _, w := range bf.bits {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+// Bits returns the number of bits of the filter.
+func (bf *BloomFilter[T]) Bits() uint64 { return bf.m }
+
+// HashFunctions returns the number of positions set for each value.
+func (bf *BloomFilter[T]) HashFunctions() uint64 { return bf.k }
+
+// Clear removes all values from the filter.
+func (bf *BloomFilter[T]) Clear() {
+	clear(bf.bits)
+}
+
+// bloomMagic starts every encoded BloomFilter.
+const bloomMagic = "BLM1"
+
+// MarshalBinary encodes the filter into bytes: a magic string, m, k and the bit array,
+// as little-endian integers.
+func (bf *BloomFilter[T]) MarshalBinary() ([]byte, error) {
+	out := make([]byte, 0, len(bloomMagic)+16+8*len(bf.bits))
+	out = append(out, bloomMagic...)
+	out = binary.LittleEndian.AppendUint64(out, bf.m)
+	out = binary.LittleEndian.AppendUint64(out, bf.k)
+
+	for _, w := range bf.bits {
+		out = binary.LittleEndian.AppendUint64(out, w)
+	}
+
+	return out, nil
+}
+
+// UnmarshalBinary decodes bytes produced by MarshalBinary, replacing the content of the filter.
+func (bf *BloomFilter[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < len(bloomMagic)+16 || string(data[:len(bloomMagic)]) != bloomMagic {
+		return ErrInvalidEncoding
+	}
+	data = data[len(bloomMagic):]
+
+	m := binary.LittleEndian.Uint64(data)
+	k := binary.LittleEndian.Uint64(data[8:])
+	data = data[16:]
+
+	// m is checked against the length first, so that rounding it up cannot overflow
+	if m == 0 || m > uint64(len(data))*8 || uint64(len(data)) != (m+63)/64*8 {
+		return ErrInvalidEncoding
+	}
+	if k == 0 || k > MaxHashFunctions {
+		return ErrInvalidEncoding
+	}
+
+	words := make([]uint64, len(data)/8)
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+
+	bf.bits, bf.m, bf.k = words, m, k
+	return nil
+}
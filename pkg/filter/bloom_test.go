@@ -0,0 +1,154 @@
+package filter_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/filter"
+	"math"
+	"testing"
+)
+
+type user struct {
+	ID   int
+	Name string
+}
+
+func TestOptimalParameters(t *testing.T) {
+	m, k := filter.OptimalParameters(1000, 0.01)
+
+	// About 9.6 bits and 7 hash functions per item
+	if m != 9586 || k != 7 {
+		t.Errorf("Expected m = 9586 and k = 7, got %d and %d", m, k)
+	}
+}
+
+func TestBloomFilter_Add(t *testing.T) {
+	bf := filter.NewBloomFilter[user](1000, 0.01)
+
+	for i := 0; i < 1000; i++ {
+		if err := bf.Add(user{i, "user"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 1000; i++ {
+		if !bf.MightContain(user{i, "user"}) {
+			t.Fatalf("Expected filter to contain user %d", i)
+		}
+	}
+
+	falsePositives := 0
+	for i := 1000; i < 11000; i++ {
+		if bf.MightContain(user{i, "user"}) {
+			falsePositives++
+		}
+	}
+
+	if rate := float64(falsePositives) / 10000; rate > 0.02 {
+		t.Errorf("Expected false positive rate to be about 0.01, got %f", rate)
+	}
+}
+
+func TestBloomFilter_Add_Unencodable(t *testing.T) {
+	bf := filter.NewBloomFilter[any](10, 0.01)
+
+	if err := bf.Add(func() {}); err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+	if bf.MightContain(func() {}) {
+		t.Errorf("Expected an unencodable value to not be contained")
+	}
+}
+
+func TestBloomFilter_Union(t *testing.T) {
+	bf1 := filter.NewBloomFilter[int](100, 0.01)
+	bf2 := filter.NewBloomFilter[int](100, 0.01)
+	_ = bf1.Add(1)
+	_ = bf2.Add(2)
+
+	if err := bf1.Union(bf2); err != nil {
+		t.Fatal(err)
+	}
+	if !bf1.MightContain(1) || !bf1.MightContain(2) {
+		t.Errorf("Expected union to contain 1 and 2")
+	}
+
+	if err := bf1.Union(filter.NewBloomFilter[int](10, 0.01)); !errors.Is(err, filter.ErrIncompatible) {
+		t.Errorf("Expected ErrIncompatible, got %v", err)
+	}
+}
+
+func TestBloomFilter_EstimatedCount(t *testing.T) {
+	bf := filter.NewBloomFilter[int](10000, 0.01)
+	for i := 0; i < 5000; i++ {
+		_ = bf.Add(i)
+	}
+
+	if count := bf.EstimatedCount(); math.Abs(count-5000) > 250 {
+		t.Errorf("Expected about 5000 items, got %f", count)
+	}
+	if rate := bf.FalsePositiveRate(); rate > 0.01 {
+		t.Errorf("Expected false positive rate below 0.01 at half capacity, got %f", rate)
+	}
+
+	bf.Clear()
+
+	if bf.EstimatedCount() != 0 || bf.MightContain(1) {
+		t.Errorf("Expected filter to be empty after Clear")
+	}
+}
+
+func TestBloomFilter_MarshalBinary(t *testing.T) {
+	bf := filter.NewBloomFilter[string](100, 0.01)
+	_ = bf.Add("a")
+	_ = bf.Add("b")
+
+	data, err := bf.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := filter.NewBloomFilterWithParameters[string](1, 1)
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if !decoded.MightContain("a") || !decoded.MightContain("b") || decoded.Bits() != bf.Bits() || decoded.HashFunctions() != bf.HashFunctions() {
+		t.Errorf("Expected decoded filter to match the original")
+	}
+
+	if err := decoded.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, filter.ErrInvalidEncoding) {
+		t.Errorf("Expected ErrInvalidEncoding, got %v", err)
+	}
+}
+
+func TestBloomFilter_UnmarshalBinary_Malformed(t *testing.T) {
+	header := func(m, k uint64, words int) []byte {
+		data := []byte("BLM1")
+		data = binary.LittleEndian.AppendUint64(data, m)
+		data = binary.LittleEndian.AppendUint64(data, k)
+		return append(data, make([]byte, 8*words)...)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"no bits", header(0, 1, 0)},
+		{"overflowing bits", header(math.MaxUint64-10, 1, 0)},
+		{"missing words", header(128, 1, 1)},
+		{"no hash functions", header(64, 0, 1)},
+		{"too many hash functions", header(64, math.MaxUint64, 1)},
+	}
+
+	for _, test := range tests {
+		bf := filter.NewBloomFilterWithParameters[int](64, 1)
+		if err := bf.UnmarshalBinary(test.data); !errors.Is(err, filter.ErrInvalidEncoding) {
+			t.Errorf("%s: expected ErrInvalidEncoding, got %v", test.name, err)
+		}
+	}
+
+	if bf := filter.NewBloomFilterWithParameters[int](64, 1000); bf.HashFunctions() != filter.MaxHashFunctions {
+		t.Errorf("Expected %d hash functions, got %d", filter.MaxHashFunctions, bf.HashFunctions())
+	}
+}
@@ -0,0 +1,129 @@
+package filter
+
+import (
+	"encoding/binary"
+	"github.com/pietroagazzi/gohashlib/pkg/utils"
+	"math"
+)
+
+// CountingBloomFilter represents a BloomFilter that supports deletions.
+// https://en.wikipedia.org/wiki/Counting_Bloom_filter
+//
+// Each position holds a counter instead of a bit, incremented when a value is added and
+// decremented when it is deleted. Counters saturate at 255 and are then never decremented,
+// which keeps the filter free of false negatives.
+type CountingBloomFilter[T any] struct {
+	counters []uint8
+	// k is the number of positions set for each value
+	k uint64
+}
+
+// NewCountingBloomFilter returns a new CountingBloomFilter sized to hold the expected number
+// of items with the given false positive rate.
+func NewCountingBloomFilter[T any](expectedItems uint, falsePositiveRate float64) *CountingBloomFilter[T] {
+	m, k := OptimalParameters(expectedItems, falsePositiveRate)
+
+	return &CountingBloomFilter[T]{counters: make([]uint8, m), k: k}
+}
+
+// Add adds a value to the filter.
+// It returns an error if the value cannot be encoded.
+func (cf *CountingBloomFilter[T]) Add(value T) error {
+	h1, h2, err := utils.DoubleHash(value)
+	if err != nil {
+		return err
+	}
+
+	locations(h1, h2, cf.k, uint64(len(cf.counters)), func(i uint64) {
+		if cf.counters[i] < math.MaxUint8 {
+			cf.counters[i]++
+		}
+	})
+
+	return nil
+}
+
+// Delete removes a value from the filter, returning false if the value was not in it.
+//
+// Only values that were added should be deleted: deleting a false positive removes
+// occurrences of other values, which may then be reported as absent.
+func (cf *CountingBloomFilter[T]) Delete(value T) bool {
+	if !cf.MightContain(value) {
+		return false
+	}
+
+	h1, h2, _ := utils.DoubleHash(value)
+	locations(h1, h2, cf.k, uint64(len(cf.counters)), func(i uint64) {
+		if cf.counters[i] < math.MaxUint8 {
+			cf.counters[i]--
+		}
+	})
+
+	return true
+}
+
+// MightContain checks if a value might have been added to the filter.
+// It returns false if the value cannot be encoded, since it could not have been added.
+func (cf *CountingBloomFilter[T]) MightContain(value T) bool {
+	h1, h2, err := utils.DoubleHash(value)
+	if err != nil {
+		return false
+	}
+
+	found := true
+	locations(h1, h2, cf.k, uint64(len(cf.counters)), func(i uint64) {
+		found = found && cf.counters[i] > 0
+	})
+
+	return found
+}
+
+// Load returns the fraction of counters that are not zero.
+func (cf *CountingBloomFilter[T]) Load() float64 {
+	used := 0
+	for _, c := range cf.counters {
+		if c > 0 {
+			used++
+		}
+	}
+
+	return float64(used) / float64(len(cf.counters))
+}
+
+// Clear removes all values from the filter.
+func (cf *CountingBloomFilter[T]) Clear() {
+	clear(cf.counters)
+}
+
+// countingMagic starts every encoded CountingBloomFilter.
+const countingMagic = "CBF1"
+
+// MarshalBinary encodes the filter into bytes: a magic string, the number of counters
+// and k as little-endian integers, then a byte per counter.
+func (cf *CountingBloomFilter[T]) MarshalBinary() ([]byte, error) {
+	out := make([]byte, 0, len(countingMagic)+16+len(cf.counters))
+	out = append(out, countingMagic...)
+	out = binary.LittleEndian.AppendUint64(out, uint64(len(cf.counters)))
+	out = binary.LittleEndian.AppendUint64(out, cf.k)
+
+	return append(out, cf.counters...), nil
+}
+
+// UnmarshalBinary decodes bytes produced by MarshalBinary, replacing the content of the filter.
+func (cf *CountingBloomFilter[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < len(countingMagic)+16 || string(data[:len(countingMagic)]) != countingMagic {
+		return ErrInvalidEncoding
+	}
+	data = data[len(countingMagic):]
+
+	m := binary.LittleEndian.Uint64(data)
+	k := binary.LittleEndian.Uint64(data[8:])
+	data = data[16:]
+
+	if m == 0 || k == 0 || k > MaxHashFunctions || uint64(len(data)) != m {
+		return ErrInvalidEncoding
+	}
+
+	cf.counters, cf.k = append([]uint8(nil), data...), k
+	return nil
+}
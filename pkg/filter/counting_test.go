@@ -0,0 +1,112 @@
+package filter_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/filter"
+	"math"
+	"testing"
+)
+
+func TestCountingBloomFilter_Delete(t *testing.T) {
+	cf := filter.NewCountingBloomFilter[user](1000, 0.01)
+
+	for i := 0; i < 1000; i++ {
+		if err := cf.Add(user{i, "user"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 1000; i += 2 {
+		if !cf.Delete(user{i, "user"}) {
+			t.Fatalf("Expected user %d to be deleted", i)
+		}
+	}
+
+	for i := 1; i < 1000; i += 2 {
+		if !cf.MightContain(user{i, "user"}) {
+			t.Fatalf("Expected filter to still contain user %d", i)
+		}
+	}
+
+	remaining := 0
+	for i := 0; i < 1000; i += 2 {
+		if cf.MightContain(user{i, "user"}) {
+			remaining++
+		}
+	}
+
+	if remaining > 20 {
+		t.Errorf("Expected deleted users to be gone, %d are still reported", remaining)
+	}
+}
+
+func TestCountingBloomFilter_Delete_Missing(t *testing.T) {
+	cf := filter.NewCountingBloomFilter[int](100, 0.01)
+	_ = cf.Add(1)
+	_ = cf.Add(1)
+
+	if cf.Delete(2) {
+		t.Errorf("Expected deleting a missing value to return false")
+	}
+
+	cf.Delete(1)
+
+	if !cf.MightContain(1) {
+		t.Errorf("Expected a value added twice to survive one deletion")
+	}
+}
+
+func TestCountingBloomFilter_Load(t *testing.T) {
+	cf := filter.NewCountingBloomFilter[int](100, 0.01)
+
+	if cf.Load() != 0 {
+		t.Errorf("Expected load to be 0, got %f", cf.Load())
+	}
+
+	for i := 0; i < 100; i++ {
+		_ = cf.Add(i)
+	}
+
+	// An optimally sized filter at capacity has about half of its positions set
+	if load := cf.Load(); load < 0.4 || load > 0.6 {
+		t.Errorf("Expected load to be about 0.5, got %f", load)
+	}
+
+	cf.Clear()
+
+	if cf.Load() != 0 {
+		t.Errorf("Expected load to be 0 after Clear, got %f", cf.Load())
+	}
+}
+
+func TestCountingBloomFilter_MarshalBinary(t *testing.T) {
+	cf := filter.NewCountingBloomFilter[string](100, 0.01)
+	_ = cf.Add("a")
+
+	data, _ := cf.MarshalBinary()
+
+	decoded := filter.NewCountingBloomFilter[string](1, 0.5)
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.MightContain("a") || !decoded.Delete("a") || decoded.MightContain("a") {
+		t.Errorf("Expected decoded filter to match the original")
+	}
+
+	if err := decoded.UnmarshalBinary([]byte("CBF1")); !errors.Is(err, filter.ErrInvalidEncoding) {
+		t.Errorf("Expected ErrInvalidEncoding, got %v", err)
+	}
+}
+
+func TestCountingBloomFilter_UnmarshalBinary_Malformed(t *testing.T) {
+	data := []byte("CBF1")
+	data = binary.LittleEndian.AppendUint64(data, 8)
+	data = binary.LittleEndian.AppendUint64(data, math.MaxUint64)
+	data = append(data, make([]byte, 8)...)
+
+	cf := filter.NewCountingBloomFilter[int](1, 0.5)
+	if err := cf.UnmarshalBinary(data); !errors.Is(err, filter.ErrInvalidEncoding) {
+		t.Errorf("Expected ErrInvalidEncoding, got %v", err)
+	}
+}
@@ -0,0 +1,241 @@
+package filter
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/utils"
+	"math/bits"
+	"math/rand"
+)
+
+// ErrFull is returned by CuckooFilter.Add when no room can be made for a value.
+var ErrFull = errors.New("filter: cuckoo filter is full")
+
+const (
+	// bucketSize is the number of fingerprints in a bucket
+	bucketSize = 4
+	// maxKicks is the number of fingerprints relocated before a filter is deemed full
+	maxKicks = 500
+)
+
+// bucket holds the fingerprints of a CuckooFilter. A zero fingerprint is an empty slot.
+type bucket [bucketSize]uint16
+
+// CuckooFilter represents a probabilistic set supporting deletions, with a lower memory
+// usage than a CountingBloomFilter for low false positive rates.
+// https://www.cs.cmu.edu/~dga/papers/cuckoo-conext2014.pdf
+//
+// Each value is stored as a 16-bit fingerprint in one of two candidate buckets of
+// four slots. The second bucket is derived from the first one and the fingerprint only,
+// so that fingerprints can be moved between their buckets without the original value.
+type CuckooFilter[T any] struct {
+	buckets []bucket
+	// count is the number of fingerprints stored
+	count int
+	// rng picks the fingerprints to relocate
+	rng *rand.Rand
+}
+
+// NewCuckooFilter returns a new CuckooFilter with room for about capacity values.
+// The number of buckets is a power of two, so the actual capacity can be larger.
+func NewCuckooFilter[T any](capacity uint) *CuckooFilter[T] {
+	// Cuckoo filters with 4-way buckets fill up to about 95%
+	n := max(uint64(capacity)*100/95/bucketSize, 1)
+	n = 1 << bits.Len64(n-1)
+
+	return &CuckooFilter[T]{buckets: make([]bucket, n), rng: rand.New(rand.NewSource(1))}
+}
+
+// fingerprint returns the fingerprint and the first bucket of a value.
+func (cf *CuckooFilter[T]) fingerprint(value T) (fp uint16, i uint64, err error) {
+	h1, h2, err := utils.DoubleHash(value)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Use the high bits of the second hash, avoiding the zero fingerprint of empty slots
+	fp = uint16(h2 >> 48)
+	if fp == 0 {
+		fp = 1
+	}
+
+	return fp, h1 & cf.mask(), nil
+}
+
+// alternate returns the other bucket of a fingerprint stored in bucket i.
+// Applying it twice returns i.
+func (cf *CuckooFilter[T]) alternate(i uint64, fp uint16) uint64 {
+	return (i ^ utils.Mix64(uint64(fp))) & cf.mask()
+}
+
+func (cf *CuckooFilter[T]) mask() uint64 {
+	return uint64(len(cf.buckets) - 1)
+}
+
+// insert stores a fingerprint in an empty slot of bucket i, returning false if it is full.
+func (cf *CuckooFilter[T]) insert(i uint64, fp uint16) bool {
+	for s, f := range cf.buckets[i] {
+		if f == 0 {
+			cf.buckets[i][s] = fp
+			return true
+		}
+	}
+	return false
+}
+
+// Add adds a value to the filter.
+//
+// When both buckets of the value are full, fingerprints are relocated to their other
+// bucket to make room. It returns ErrFull, leaving the filter unchanged, if no room is
+// found, or an error if the value cannot be encoded.
+func (cf *CuckooFilter[T]) Add(value T) error {
+	fp, i1, err := cf.fingerprint(value)
+	if err != nil {
+		return err
+	}
+
+	i2 := cf.alternate(i1, fp)
+	if cf.insert(i1, fp) || cf.insert(i2, fp) {
+		cf.count++
+		return nil
+	}
+
+	// swap is a relocation, kept to undo them if no room is found
+	type swap struct {
+		i    uint64
+		slot int
+	}
+	var swaps []swap
+
+	i := []uint64{i1, i2}[cf.rng.Intn(2)]
+	for range maxKicks {
+		slot := cf.rng.Intn(bucketSize)
+		fp, cf.buckets[i][slot] = cf.buckets[i][slot], fp
+		swaps = append(swaps, swap{i, slot})
+
+		i = cf.alternate(i, fp)
+		if cf.insert(i, fp) {
+			cf.count++
+			return nil
+		}
+	}
+
+	// Put the evicted fingerprints back, in reverse order
+	for j := len(swaps) - 1; j >= 0; j-- {
+		s := swaps[j]
+		fp, cf.buckets[s.i][s.slot] = cf.buckets[s.i][s.slot], fp
+	}
+
+	return ErrFull
+}
+
+// Delete removes a value from the filter, returning false if the value was not in it.
+//
+// Only values that were added should be deleted: deleting a false positive removes
+// the fingerprint of another value.
+func (cf *CuckooFilter[T]) Delete(value T) bool {
+	fp, i1, err := cf.fingerprint(value)
+	if err != nil {
+		return false
+	}
+
+	for _, i := range []uint64{i1, cf.alternate(i1, fp)} {
+		for s, f := range cf.buckets[i] {
+			if f == fp {
+				cf.buckets[i][s] = 0
+				cf.count--
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// MightContain checks if a value might have been added to the filter.
+// It returns false if the value cannot be encoded, since it could not have been added.
+func (cf *CuckooFilter[T]) MightContain(value T) bool {
+	fp, i1, err := cf.fingerprint(value)
+	if err != nil {
+		return false
+	}
+
+	for _, i := range []uint64{i1, cf.alternate(i1, fp)} {
+		for _, f := range cf.buckets[i] {
+			if f == fp {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Len returns the number of values in the filter.
+func (cf *CuckooFilter[T]) Len() int {
+	return cf.count
+}
+
+// Load returns the fraction of slots in use.
+func (cf *CuckooFilter[T]) Load() float64 {
+	return float64(cf.count) / float64(len(cf.buckets)*bucketSize)
+}
+
+// Clear removes all values from the filter.
+func (cf *CuckooFilter[T]) Clear() {
+	clear(cf.buckets)
+	cf.count = 0
+}
+
+// cuckooMagic starts every encoded CuckooFilter.
+const cuckooMagic = "CKF1"
+
+// MarshalBinary encodes the filter into bytes: a magic string, the number of buckets
+// and then the fingerprints of each bucket, as little-endian integers.
+func (cf *CuckooFilter[T]) MarshalBinary() ([]byte, error) {
+	out := make([]byte, 0, len(cuckooMagic)+8+2*bucketSize*len(cf.buckets))
+	out = append(out, cuckooMagic...)
+	out = binary.LittleEndian.AppendUint64(out, uint64(len(cf.buckets)))
+
+	for _, b := range cf.buckets {
+		for _, fp := range b {
+			out = binary.LittleEndian.AppendUint16(out, fp)
+		}
+	}
+
+	return out, nil
+}
+
+// UnmarshalBinary decodes bytes produced by MarshalBinary, replacing the content of the filter.
+func (cf *CuckooFilter[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < len(cuckooMagic)+8 || string(data[:len(cuckooMagic)]) != cuckooMagic {
+		return ErrInvalidEncoding
+	}
+	data = data[len(cuckooMagic):]
+
+	n := binary.LittleEndian.Uint64(data)
+	data = data[8:]
+
+	// n is checked against the length by division, so that it cannot overflow
+	if n == 0 || n&(n-1) != 0 || uint64(len(data))%(2*bucketSize) != 0 || uint64(len(data))/(2*bucketSize) != n {
+		return ErrInvalidEncoding
+	}
+
+	buckets := make([]bucket, n)
+	count := 0
+	for i := range buckets {
+		for s := range buckets[i] {
+			buckets[i][s] = binary.LittleEndian.Uint16(data[2*(i*bucketSize+s):])
+			if buckets[i][s] != 0 {
+				count++
+			}
+		}
+	}
+
+	cf.buckets, cf.count = buckets, count
+	if cf.rng == nil {
+		cf.rng = rand.New(rand.NewSource(1))
+	}
+
+	return nil
+}
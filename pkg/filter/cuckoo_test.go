@@ -0,0 +1,126 @@
+package filter_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/filter"
+	"testing"
+)
+
+func TestCuckooFilter_Add(t *testing.T) {
+	cf := filter.NewCuckooFilter[user](1000)
+
+	for i := 0; i < 1000; i++ {
+		if err := cf.Add(user{i, "user"}); err != nil {
+			t.Fatalf("Adding user %d: %v", i, err)
+		}
+	}
+
+	for i := 0; i < 1000; i++ {
+		if !cf.MightContain(user{i, "user"}) {
+			t.Fatalf("Expected filter to contain user %d", i)
+		}
+	}
+
+	falsePositives := 0
+	for i := 1000; i < 11000; i++ {
+		if cf.MightContain(user{i, "user"}) {
+			falsePositives++
+		}
+	}
+
+	if rate := float64(falsePositives) / 10000; rate > 0.001 {
+		t.Errorf("Expected false positive rate below 0.001, got %f", rate)
+	}
+	if cf.Len() != 1000 {
+		t.Errorf("Expected length to be 1000, got %d", cf.Len())
+	}
+}
+
+func TestCuckooFilter_Add_Full(t *testing.T) {
+	cf := filter.NewCuckooFilter[int](8)
+
+	added := 0
+	for i := 0; i < 100; i++ {
+		if err := cf.Add(i); errors.Is(err, filter.ErrFull) {
+			break
+		}
+		added++
+	}
+
+	if added == 100 {
+		t.Fatalf("Expected filter to fill up")
+	}
+
+	// A failed Add leaves the previous values in place
+	for i := 0; i < added; i++ {
+		if !cf.MightContain(i) {
+			t.Errorf("Expected filter to contain %d after it filled up", i)
+		}
+	}
+	if cf.Len() != added || cf.Load() > 1 {
+		t.Errorf("Expected length to be %d, got %d", added, cf.Len())
+	}
+}
+
+func TestCuckooFilter_Delete(t *testing.T) {
+	cf := filter.NewCuckooFilter[int](100)
+	for i := 0; i < 100; i++ {
+		_ = cf.Add(i)
+	}
+
+	for i := 0; i < 100; i += 2 {
+		if !cf.Delete(i) {
+			t.Errorf("Expected %d to be deleted", i)
+		}
+	}
+
+	for i := 1; i < 100; i += 2 {
+		if !cf.MightContain(i) {
+			t.Errorf("Expected filter to still contain %d", i)
+		}
+	}
+
+	if cf.Delete(1000) {
+		t.Errorf("Expected deleting a missing value to return false")
+	}
+	if cf.Len() != 50 {
+		t.Errorf("Expected length to be 50, got %d", cf.Len())
+	}
+
+	cf.Clear()
+
+	if cf.Len() != 0 || cf.Load() != 0 || cf.MightContain(1) {
+		t.Errorf("Expected filter to be empty after Clear")
+	}
+}
+
+func TestCuckooFilter_MarshalBinary(t *testing.T) {
+	cf := filter.NewCuckooFilter[string](100)
+	_ = cf.Add("a")
+	_ = cf.Add("b")
+
+	data, _ := cf.MarshalBinary()
+
+	decoded := filter.NewCuckooFilter[string](1)
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.MightContain("a") || !decoded.MightContain("b") || decoded.Len() != 2 {
+		t.Errorf("Expected decoded filter to match the original")
+	}
+
+	if err := decoded.UnmarshalBinary(data[:len(data)-2]); !errors.Is(err, filter.ErrInvalidEncoding) {
+		t.Errorf("Expected ErrInvalidEncoding, got %v", err)
+	}
+}
+
+func TestCuckooFilter_UnmarshalBinary_Malformed(t *testing.T) {
+	// A number of buckets overflowing the expected length, with no buckets
+	data := binary.LittleEndian.AppendUint64([]byte("CKF1"), 1<<61)
+
+	cf := filter.NewCuckooFilter[int](1)
+	if err := cf.UnmarshalBinary(data); !errors.Is(err, filter.ErrInvalidEncoding) {
+		t.Errorf("Expected ErrInvalidEncoding, got %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package hashmap
+
+import (
+	"errors"
+	"fmt"
+	"github.com/pietroagazzi/gohashlib/pkg/utils"
+)
+
+// ErrConflict is returned when applying a patch to a Map that does not hold
+// the entries the patch was computed from.
+var ErrConflict = errors.New("hashmap: patch does not apply")
+
+// Change is an entry whose value changed.
+type Change[K, V any] struct {
+	Key K
+	Old V
+	New V
+}
+
+// Patch represents the differences between two Maps, as returned by Diff.
+type Patch[K, V any] struct {
+	// Added holds the entries only in the second Map
+	Added []Entry[K, V]
+	// Removed holds the entries only in the first Map
+	Removed []Entry[K, V]
+	// Changed holds the keys in both Maps with different values
+	Changed []Change[K, V]
+}
+
+// Diff returns the patch turning the Map a into the Map b.
+// Values are compared with utils.Equaler.
+func Diff[K, V any](a, b *Map[K, V]) *Patch[K, V] {
+	p := &Patch[K, V]{}
+
+	for key, old := range a.All() {
+		value, ok := b.Get(key)
+
+		switch {
+		case !ok:
+			p.Removed = append(p.Removed, Entry[K, V]{Key: key, Value: old})
+		case !utils.Equaler(old, value):
+			p.Changed = append(p.Changed, Change[K, V]{Key: key, Old: old, New: value})
+		}
+	}
+
+	for key, value := range b.All() {
+		if _, ok := a.Get(key); !ok {
+			p.Added = append(p.Added, Entry[K, V]{Key: key, Value: value})
+		}
+	}
+
+	return p
+}
+
+// Len returns the number of entries added, removed and changed.
+func (p *Patch[K, V]) Len() int {
+	return len(p.Added) + len(p.Removed) + len(p.Changed)
+}
+
+// Invert returns the patch undoing the patch: it removes the entries added,
+// adds back the entries removed, and changes the values back.
+func (p *Patch[K, V]) Invert() *Patch[K, V] {
+	result := &Patch[K, V]{
+		Added:   append([]Entry[K, V](nil), p.Removed...),
+		Removed: append([]Entry[K, V](nil), p.Added...),
+	}
+
+	for _, c := range p.Changed {
+		result.Changed = append(result.Changed, Change[K, V]{Key: c.Key, Old: c.New, New: c.Old})
+	}
+
+	return result
+}
+
+// String returns a string representation of the patch, one line per entry.
+func (p *Patch[K, V]) String() string {
+	str := ""
+
+	for _, e := range p.Added {
+		str += fmt.Sprintf("+ %v: %v\n", e.Key, e.Value)
+	}
+	for _, e := range p.Removed {
+		str += fmt.Sprintf("- %v: %v\n", e.Key, e.Value)
+	}
+	for _, c := range p.Changed {
+		str += fmt.Sprintf("~ %v: %v -> %v\n", c.Key, c.Old, c.New)
+	}
+
+	return str
+}
+
+// Apply applies a patch to the Map.
+//
+// The Map must hold the entries the patch was computed from: the keys added must be absent,
+// and the keys removed or changed must hold their old values. Otherwise it returns an
+// ErrConflict naming the first mismatching key, and leaves the Map unchanged.
+func (ht *Map[K, V]) Apply(p *Patch[K, V]) error {
+	for _, e := range p.Added {
+		if _, ok := ht.Get(e.Key); ok {
+			return fmt.Errorf("%w: key %v already exists", ErrConflict, e.Key)
+		}
+	}
+	for _, e := range p.Removed {
+		if value, ok := ht.Get(e.Key); !ok || !utils.Equaler(value, e.Value) {
+			return fmt.Errorf("%w: key %v does not hold %v", ErrConflict, e.Key, e.Value)
+		}
+	}
+	for _, c := range p.Changed {
+		if value, ok := ht.Get(c.Key); !ok || !utils.Equaler(value, c.Old) {
+			return fmt.Errorf("%w: key %v does not hold %v", ErrConflict, c.Key, c.Old)
+		}
+	}
+
+	for _, e := range p.Removed {
+		ht.Delete(e.Key)
+	}
+	for _, e := range p.Added {
+		ht.Set(e.Key, e.Value)
+	}
+	for _, c := range p.Changed {
+		ht.Set(c.Key, c.New)
+	}
+
+	return nil
+}
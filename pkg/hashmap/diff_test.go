@@ -0,0 +1,90 @@
+package hashmap_test
+
+import (
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"testing"
+)
+
+type limits struct {
+	CPU    int
+	Memory []string
+}
+
+// configs returns two versions of a config, differing by an added, a removed and a changed key.
+func configs() (a, b *hashmap.Map[string, limits]) {
+	a = hashmap.NewMap[string, limits](2, 0.75)
+	a.Set("api", limits{2, []string{"512Mi"}})
+	a.Set("worker", limits{4, []string{"1Gi"}})
+	a.Set("cron", limits{1, nil})
+
+	b = hashmap.NewMap[string, limits](2, 0.75)
+	b.Set("api", limits{2, []string{"512Mi"}})
+	b.Set("worker", limits{4, []string{"2Gi"}})
+	b.Set("db", limits{8, []string{"8Gi"}})
+
+	return a, b
+}
+
+func TestDiff(t *testing.T) {
+	a, b := configs()
+
+	p := hashmap.Diff(a, b)
+
+	if p.Len() != 3 || len(p.Added) != 1 || len(p.Removed) != 1 || len(p.Changed) != 1 {
+		t.Fatalf("Expected an added, a removed and a changed key, got\n%v", p)
+	}
+	if p.Added[0].Key != "db" || p.Removed[0].Key != "cron" {
+		t.Errorf("Expected db added and cron removed, got\n%v", p)
+	}
+	if c := p.Changed[0]; c.Key != "worker" || c.Old.Memory[0] != "1Gi" || c.New.Memory[0] != "2Gi" {
+		t.Errorf("Expected worker changed from 1Gi to 2Gi, got %v", c)
+	}
+
+	if p := hashmap.Diff(a, a); p.Len() != 0 {
+		t.Errorf("Expected no differences, got\n%v", p)
+	}
+}
+
+func TestMap_Apply(t *testing.T) {
+	a, b := configs()
+	p := hashmap.Diff(a, b)
+
+	if err := a.Apply(p); err != nil {
+		t.Fatal(err)
+	}
+	if !a.Equal(b) {
+		t.Errorf("Expected %v, got %v", b, a)
+	}
+
+	// The inverted patch undoes it
+	original, _ := configs()
+	if err := a.Apply(p.Invert()); err != nil {
+		t.Fatal(err)
+	}
+	if !a.Equal(original) {
+		t.Errorf("Expected %v, got %v", original, a)
+	}
+}
+
+func TestMap_Apply_Conflict(t *testing.T) {
+	a, b := configs()
+	p := hashmap.Diff(a, b)
+
+	a.Set("worker", limits{4, []string{"4Gi"}})
+	before, _ := a.Digest()
+
+	if err := a.Apply(p); !errors.Is(err, hashmap.ErrConflict) {
+		t.Errorf("Expected ErrConflict, got %v", err)
+	}
+	if after, _ := a.Digest(); after != before {
+		t.Errorf("Expected the Map to be unchanged")
+	}
+
+	// Applying twice conflicts on the added key
+	_ = b.Apply(p.Invert())
+	_ = b.Apply(p)
+	if err := b.Apply(p); !errors.Is(err, hashmap.ErrConflict) {
+		t.Errorf("Expected ErrConflict, got %v", err)
+	}
+}
@@ -0,0 +1,23 @@
+package hashmap
+
+import "github.com/pietroagazzi/gohashlib/pkg/utils"
+
+// Digest returns a hash of the entries of the Map, which does not depend on their order
+// or on the size of the Map. Maps with equal entries have equal digests, and Maps with
+// different entries have different digests with high probability.
+//
+// The digest is the sum of utils.HashEntry of each entry.
+// It returns an error if a key or a value cannot be encoded.
+func (ht *Map[K, V]) Digest() (uint64, error) {
+	digest := uint64(0)
+
+	for key, value := range ht.All() {
+		h, err := utils.HashEntry(key, value)
+		if err != nil {
+			return 0, err
+		}
+		digest += h
+	}
+
+	return digest, nil
+}
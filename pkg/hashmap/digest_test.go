@@ -0,0 +1,47 @@
+package hashmap_test
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"testing"
+)
+
+func TestMap_Digest(t *testing.T) {
+	m1 := hashmap.NewMap[int, string](2, 0.75)
+	m1.Set(1, "one")
+	m1.Set(2, "two")
+	m1.Set(3, "three")
+
+	// Same entries, set in another order into a Map of another size
+	m2 := hashmap.NewMap[int, string](50, 0.75)
+	m2.Set(3, "three")
+	m2.Set(2, "two")
+	m2.Set(1, "one")
+
+	d1, err := m1.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d2, _ := m2.Digest(); d1 != d2 {
+		t.Errorf("Expected equal digests, got %d and %d", d1, d2)
+	}
+
+	m2.Set(2, "deux")
+	if d2, _ := m2.Digest(); d1 == d2 {
+		t.Errorf("Expected digests to differ after changing a value")
+	}
+
+	// Swapping values between keys changes the digest
+	m2.Set(2, "three")
+	m2.Set(3, "two")
+	if d2, _ := m2.Digest(); d1 == d2 {
+		t.Errorf("Expected digests to differ after swapping values")
+	}
+}
+
+func TestMap_Digest_Empty(t *testing.T) {
+	m := hashmap.NewMap[int, string](0, 0.75)
+
+	if d, err := m.Digest(); d != 0 || err != nil {
+		t.Errorf("Expected digest 0, got %d and %v", d, err)
+	}
+}
@@ -0,0 +1,112 @@
+package hashmap
+
+// CollisionPolicy returns the value to keep when two items end up with the same key.
+type CollisionPolicy[V any] func(existing, incoming V) V
+
+// KeepExisting is a CollisionPolicy keeping the value already in the Map.
+func KeepExisting[V any](existing, _ V) V { return existing }
+
+// KeepIncoming is a CollisionPolicy replacing the value already in the Map.
+func KeepIncoming[V any](_, incoming V) V { return incoming }
+
+// Filter returns a new Map with the items of the Map that satisfy the predicate.
+func Filter[K, V any](m *Map[K, V], predicate func(K, V) bool) *Map[K, V] {
+	result := NewMap[K, V](m.Size(), m.Threshold)
+
+	for k, v := range m.All() {
+		if predicate(k, v) {
+			result.Set(k, v)
+		}
+	}
+
+	return result
+}
+
+// MapValues returns a new Map with the same keys, and values transformed by f.
+func MapValues[K, V, W any](m *Map[K, V], f func(K, V) W) *Map[K, W] {
+	result := NewMap[K, W](m.Size(), m.Threshold)
+
+	for k, v := range m.All() {
+		result.Set(k, f(k, v))
+	}
+
+	return result
+}
+
+// MapKeys returns a new Map with keys transformed by f.
+// When several keys are transformed into the same key, the policy picks the value to keep.
+func MapKeys[K, V, J any](m *Map[K, V], f func(K, V) J, policy CollisionPolicy[V]) *Map[J, V] {
+	result := NewMap[J, V](m.Size(), m.Threshold)
+
+	for k, v := range m.All() {
+		key := f(k, v)
+
+		if existing, ok := result.Get(key); ok {
+			v = policy(existing, v)
+		}
+
+		result.Set(key, v)
+	}
+
+	return result
+}
+
+// Reduce combines all items of the Map into a single value, starting from initial.
+func Reduce[K, V, A any](m *Map[K, V], initial A, f func(A, K, V) A) A {
+	acc := initial
+
+	for k, v := range m.All() {
+		acc = f(acc, k, v)
+	}
+
+	return acc
+}
+
+// Partition splits the Map into the items that satisfy the predicate and the items that do not.
+func Partition[K, V any](m *Map[K, V], predicate func(K, V) bool) (matched, rest *Map[K, V]) {
+	matched = NewMap[K, V](m.Size(), m.Threshold)
+	rest = NewMap[K, V](m.Size(), m.Threshold)
+
+	for k, v := range m.All() {
+		if predicate(k, v) {
+			matched.Set(k, v)
+		} else {
+			rest.Set(k, v)
+		}
+	}
+
+	return matched, rest
+}
+
+// ForEach calls f for each item of the Map.
+func ForEach[K, V any](m *Map[K, V], f func(K, V)) {
+	for k, v := range m.All() {
+		f(k, v)
+	}
+}
+
+// GroupBy returns a Map from each key to the items of the slice with that key, in order.
+func GroupBy[T, K any](items []T, key func(T) K) *Map[K, []T] {
+	result := NewMap[K, []T](0, DefaultThreshold)
+
+	for _, item := range items {
+		k := key(item)
+		group, _ := result.Get(k)
+		result.Set(k, append(group, item))
+	}
+
+	return result
+}
+
+// CountBy returns a Map from each key to the number of items of the slice with that key.
+func CountBy[T, K any](items []T, key func(T) K) *Map[K, int] {
+	result := NewMap[K, int](0, DefaultThreshold)
+
+	for _, item := range items {
+		k := key(item)
+		count, _ := result.Get(k)
+		result.Set(k, count+1)
+	}
+
+	return result
+}
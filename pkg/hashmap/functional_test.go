@@ -0,0 +1,139 @@
+package hashmap_test
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"strings"
+	"testing"
+)
+
+func newNumbers() *hashmap.Map[int, string] {
+	m := hashmap.NewMap[int, string](2, 0.75)
+	m.Set(1, "one")
+	m.Set(2, "two")
+	m.Set(3, "three")
+	m.Set(4, "four")
+	return m
+}
+
+func TestMap_All(t *testing.T) {
+	m := newNumbers()
+
+	sum := 0
+	for k := range m.All() {
+		sum += k
+	}
+
+	if sum != 10 {
+		t.Errorf("Expected sum of keys to be 10, got %d", sum)
+	}
+
+	count := 0
+	for range m.All() {
+		count++
+		break
+	}
+
+	if count != 1 {
+		t.Errorf("Expected iteration to stop after break, got %d items", count)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	even := hashmap.Filter(newNumbers(), func(k int, _ string) bool { return k%2 == 0 })
+
+	if even.Len() != 2 {
+		t.Errorf("Expected length to be 2, got %d", even.Len())
+	}
+	if _, ok := even.Get(1); ok {
+		t.Errorf("Expected key 1 to be filtered out")
+	}
+}
+
+func TestMapValues(t *testing.T) {
+	lengths := hashmap.MapValues(newNumbers(), func(_ int, v string) int { return len(v) })
+
+	if value, _ := lengths.Get(3); value != 5 {
+		t.Errorf("Expected value to be 5, got %d", value)
+	}
+}
+
+func TestMapKeys(t *testing.T) {
+	m := newNumbers()
+	parity := func(k int, _ string) bool { return k%2 == 0 }
+	longest := func(existing, incoming string) string {
+		if len(incoming) > len(existing) {
+			return incoming
+		}
+		return existing
+	}
+
+	result := hashmap.MapKeys(m, parity, longest)
+
+	if result.Len() != 2 {
+		t.Errorf("Expected length to be 2, got %d", result.Len())
+	}
+	if value, _ := result.Get(false); value != "three" {
+		t.Errorf("Expected value to be three, got %s", value)
+	}
+	if value, _ := result.Get(true); value != "four" {
+		t.Errorf("Expected value to be four, got %s", value)
+	}
+}
+
+func TestMapKeys_Policies(t *testing.T) {
+	m := hashmap.NewMap[int, string](2, 0.75)
+	m.Set(1, "one")
+	constant := func(int, string) int { return 0 }
+
+	if value, _ := hashmap.MapKeys(m, constant, hashmap.KeepExisting[string]).Get(0); value != "one" {
+		t.Errorf("Expected value to be one, got %s", value)
+	}
+	if hashmap.KeepExisting("a", "b") != "a" || hashmap.KeepIncoming("a", "b") != "b" {
+		t.Errorf("Unexpected collision policies")
+	}
+}
+
+func TestReduce(t *testing.T) {
+	total := hashmap.Reduce(newNumbers(), 0, func(acc, k int, _ string) int { return acc + k })
+
+	if total != 10 {
+		t.Errorf("Expected total to be 10, got %d", total)
+	}
+}
+
+func TestPartition(t *testing.T) {
+	short, long := hashmap.Partition(newNumbers(), func(_ int, v string) bool { return len(v) == 3 })
+
+	if short.Len() != 2 || long.Len() != 2 {
+		t.Errorf("Expected 2 and 2 items, got %d and %d", short.Len(), long.Len())
+	}
+}
+
+func TestForEach(t *testing.T) {
+	var names []string
+	hashmap.ForEach(newNumbers(), func(_ int, v string) { names = append(names, v) })
+
+	if len(names) != 4 {
+		t.Errorf("Expected 4 calls, got %d", len(names))
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	words := []string{"apple", "avocado", "banana", "blueberry", "cherry"}
+	groups := hashmap.GroupBy(words, func(w string) string { return w[:1] })
+
+	if groups.Len() != 3 {
+		t.Errorf("Expected 3 groups, got %d", groups.Len())
+	}
+	if group, _ := groups.Get("b"); strings.Join(group, ",") != "banana,blueberry" {
+		t.Errorf("Expected banana,blueberry, got %v", group)
+	}
+}
+
+func TestCountBy(t *testing.T) {
+	counts := hashmap.CountBy([]int{1, 2, 3, 4, 5}, func(i int) bool { return i%2 == 0 })
+
+	if count, _ := counts.Get(false); count != 3 {
+		t.Errorf("Expected 3 odd numbers, got %d", count)
+	}
+}
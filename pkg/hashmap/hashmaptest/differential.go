@@ -0,0 +1,162 @@
+package hashmaptest
+
+import (
+	"fmt"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/internal/shrink"
+	"github.com/pietroagazzi/gohashlib/pkg/utils"
+	"math/rand"
+	"strings"
+)
+
+// OpKind is the kind of an operation on a map.
+type OpKind int
+
+const (
+	OpSet OpKind = iota
+	OpGet
+	OpDelete
+)
+
+// Op is an operation on a map.
+// Keys and values are referred to by their index, as passed to the key and value functions.
+type Op struct {
+	Kind  OpKind
+	Key   int
+	Value int
+}
+
+// String returns the operation as k<key> and v<value> indices, e.g. "Set(k3, v7)".
+func (o Op) String() string {
+	switch o.Kind {
+	case OpSet:
+		return fmt.Sprintf("Set(k%d, v%d)", o.Key, o.Value)
+	case OpGet:
+		return fmt.Sprintf("Get(k%d)", o.Key)
+	}
+	return fmt.Sprintf("Delete(k%d)", o.Key)
+}
+
+// Mismatch is the error returned by Check when a map behaves differently from Go's built-in map.
+type Mismatch struct {
+	// Ops is the sequence of operations, ending with the first one whose outcome differed
+	Ops []Op
+	// Reason describes the difference
+	Reason string
+}
+
+func (m *Mismatch) Error() string {
+	ops := make([]string, len(m.Ops))
+	for i, o := range m.Ops {
+		ops[i] = o.String()
+	}
+	return fmt.Sprintf("%s after %d operations:\n\t%s", m.Reason, len(m.Ops), strings.Join(ops, "\n\t"))
+}
+
+// Check applies the operations to a new map and to a built-in map, returning a
+// *Mismatch as soon as their contents or the results of Get differ.
+// A panic of the map is reported as a mismatch too.
+func Check[K, V any](newMap func() hashmap.Interface[K, V], key func(i int) K, value func(i int) V, ops []Op) (err error) {
+	m := newMap()
+	model := make(map[int]int)
+	done := 0
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = &Mismatch{Ops: ops[:min(done+1, len(ops))], Reason: fmt.Sprintf("panic: %v", r)}
+		}
+	}()
+
+	for i, o := range ops {
+		done = i
+
+		switch o.Kind {
+		case OpSet:
+			m.Set(key(o.Key), value(o.Value))
+			model[o.Key] = o.Value
+		case OpDelete:
+			m.Delete(key(o.Key))
+			delete(model, o.Key)
+		case OpGet:
+			v, ok := m.Get(key(o.Key))
+			expected, found := model[o.Key]
+
+			if ok != found || found && !utils.Equaler(v, value(expected)) {
+				reason := fmt.Sprintf("Get(k%d) = %v, %t; expected %v, %t", o.Key, v, ok, value(expected), found)
+				return &Mismatch{Ops: ops[:i+1], Reason: reason}
+			}
+		}
+
+		if m.Len() != len(model) {
+			return &Mismatch{Ops: ops[:i+1], Reason: fmt.Sprintf("Len() = %d; expected %d", m.Len(), len(model))}
+		}
+	}
+
+	if reason := compareAll(m, model, key, value); reason != "" {
+		return &Mismatch{Ops: ops, Reason: reason}
+	}
+
+	return nil
+}
+
+// compareAll returns why the items yielded by All differ from the model, or "" if they do not.
+func compareAll[K, V any](m hashmap.Interface[K, V], model map[int]int, key func(i int) K, value func(i int) V) string {
+	seen := make(map[int]bool)
+
+	for k, v := range m.All() {
+		// Find the index of the key among the keys of the model
+		index := -1
+		for i := range model {
+			if utils.Equaler(key(i), k) {
+				index = i
+				break
+			}
+		}
+
+		switch {
+		case index < 0:
+			return fmt.Sprintf("All() yielded unexpected key %v", k)
+		case seen[index]:
+			return fmt.Sprintf("All() yielded k%d twice", index)
+		case !utils.Equaler(v, value(model[index])):
+			return fmt.Sprintf("All() yielded k%d: %v; expected %v", index, v, value(model[index]))
+		}
+		seen[index] = true
+	}
+
+	if len(seen) != len(model) {
+		return fmt.Sprintf("All() yielded %d items; expected %d", len(seen), len(model))
+	}
+
+	return ""
+}
+
+// RandomOps returns n random operations over keys and values with indices lower than keys.
+// Deletions are rarer than insertions, so that the map grows and resizes.
+func RandomOps(rng *rand.Rand, n, keys int) []Op {
+	ops := make([]Op, n)
+
+	for i := range ops {
+		o := Op{Key: rng.Intn(keys), Value: rng.Intn(keys)}
+
+		switch r := rng.Intn(10); {
+		case r < 5:
+			o.Kind = OpSet
+		case r < 8:
+			o.Kind = OpGet
+		default:
+			o.Kind = OpDelete
+		}
+
+		ops[i] = o
+	}
+
+	return ops
+}
+
+// Minimize returns the shortest subsequence of ops it finds that still makes Check fail.
+func Minimize[K, V any](newMap func() hashmap.Interface[K, V], key func(i int) K, value func(i int) V, ops []Op) []Op {
+	return shrink.Minimize(ops, func(ops []Op) bool {
+		return Check(newMap, key, value, ops) != nil
+	})
+}
@@ -0,0 +1,172 @@
+// Package hashmaptest provides a conformance test suite for implementations of hashmap.Interface.
+//
+// A wrapper or an alternative implementation can check that it behaves like hashmap.Map:
+//
+//	func TestMyMap(t *testing.T) {
+//		hashmaptest.Run(t, func() hashmap.Interface[int, int] { return NewMyMap[int, int]() }, hashmaptest.Ints, hashmaptest.Ints)
+//	}
+package hashmaptest
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/utils"
+	"math/rand"
+	"testing"
+)
+
+// Ints returns i, to run the suite on maps of int.
+func Ints(i int) int { return i }
+
+// Run runs the conformance suite against the maps returned by newMap.
+//
+// Besides checking each method, it replays random sequences of operations on the map and
+// on Go's built-in map, with few keys to exercise overwrites and collisions, and with many
+// keys to cross resize boundaries. On a mismatch it reports the seed of the sequence and
+// a minimal sequence of operations reproducing it.
+//
+// newMap must return a new empty map on every call. key must return distinct keys
+// for distinct non-negative i, and value distinct values.
+func Run[K, V any](t *testing.T, newMap func() hashmap.Interface[K, V], key func(i int) K, value func(i int) V) {
+	t.Helper()
+
+	t.Run("Empty", func(t *testing.T) {
+		m := newMap()
+
+		if m.Len() != 0 {
+			t.Errorf("Expected length to be 0, got %d", m.Len())
+		}
+		if _, ok := m.Get(key(0)); ok {
+			t.Errorf("Expected empty map to not contain %v", key(0))
+		}
+		for k := range m.All() {
+			t.Errorf("Expected empty map to yield nothing, got %v", k)
+		}
+
+		// Deleting from an empty map is a no-op
+		m.Delete(key(0))
+	})
+
+	t.Run("Set", func(t *testing.T) {
+		m := newMap()
+		m.Set(key(1), value(1))
+		m.Set(key(2), value(2))
+
+		if m.Len() != 2 {
+			t.Errorf("Expected length to be 2, got %d", m.Len())
+		}
+		if v, ok := m.Get(key(1)); !ok || !utils.Equaler(v, value(1)) {
+			t.Errorf("Expected to get %v, got %v", value(1), v)
+		}
+		if _, ok := m.Get(key(3)); ok {
+			t.Errorf("Expected map to not contain %v", key(3))
+		}
+	})
+
+	t.Run("Set_Overwrite", func(t *testing.T) {
+		m := newMap()
+		m.Set(key(1), value(1))
+		m.Set(key(1), value(2))
+
+		if v, _ := m.Get(key(1)); m.Len() != 1 || !utils.Equaler(v, value(2)) {
+			t.Errorf("Expected a single item with value %v, got %d items and %v", value(2), m.Len(), v)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		m := newMap()
+		m.Set(key(1), value(1))
+		m.Set(key(2), value(2))
+		m.Delete(key(1))
+		m.Delete(key(3))
+
+		if _, ok := m.Get(key(1)); ok || m.Len() != 1 {
+			t.Errorf("Expected %v to be deleted", key(1))
+		}
+		if _, ok := m.Get(key(2)); !ok {
+			t.Errorf("Expected %v to be kept", key(2))
+		}
+	})
+
+	t.Run("All", func(t *testing.T) {
+		m := newMap()
+		for i := 0; i < 100; i++ {
+			m.Set(key(i), value(i))
+		}
+
+		count := 0
+		for k, v := range m.All() {
+			if got, ok := m.Get(k); !ok || !utils.Equaler(got, v) {
+				t.Errorf("All yielded %v: %v, which the map does not contain", k, v)
+			}
+			count++
+		}
+
+		if count != 100 || m.Len() != 100 {
+			t.Errorf("Expected 100 items, got %d yielded and length %d", count, m.Len())
+		}
+	})
+
+	t.Run("All_Break", func(t *testing.T) {
+		m := newMap()
+		m.Set(key(1), value(1))
+		m.Set(key(2), value(2))
+
+		count := 0
+		for range m.All() {
+			count++
+			break
+		}
+
+		if count != 1 {
+			t.Errorf("Expected iteration to stop after break, got %d items", count)
+		}
+	})
+
+	t.Run("Differential", func(t *testing.T) {
+		for _, keys := range []int{4, 32, 512} {
+			for seed := int64(0); seed < 20; seed++ {
+				ops := RandomOps(rand.New(rand.NewSource(seed)), 400, keys)
+
+				if err := Check(newMap, key, value, ops); err != nil {
+					t.Fatalf("sequence with %d keys and seed %d: %v", keys, seed, Check(newMap, key, value, Minimize(newMap, key, value, ops)))
+				}
+			}
+		}
+	})
+
+	t.Run("Resize", func(t *testing.T) {
+		// Grow the map one key at a time, then shrink it, checking it at each step
+		var ops []Op
+		for i := 0; i < 300; i++ {
+			ops = append(ops, Op{Kind: OpSet, Key: i, Value: i}, Op{Kind: OpGet, Key: i}, Op{Kind: OpGet, Key: i / 2})
+		}
+		for i := 0; i < 300; i++ {
+			ops = append(ops, Op{Kind: OpDelete, Key: i}, Op{Kind: OpGet, Key: i}, Op{Kind: OpGet, Key: 299 - i})
+		}
+
+		if err := Check(newMap, key, value, ops); err != nil {
+			t.Fatal(Check(newMap, key, value, Minimize(newMap, key, value, ops)))
+		}
+	})
+
+	t.Run("Collisions", func(t *testing.T) {
+		// Fill the map, then delete the keys in an order that unlinks the heads,
+		// the middles and the tails of the chains
+		var ops []Op
+		for i := 0; i < 200; i++ {
+			ops = append(ops, Op{Kind: OpSet, Key: i, Value: i})
+		}
+		for _, step := range []int{7, 3, 2, 1} {
+			for i := 0; i < 200; i += step {
+				ops = append(ops, Op{Kind: OpDelete, Key: i}, Op{Kind: OpGet, Key: i + 1})
+			}
+			for i := 0; i < 200; i += step + 1 {
+				ops = append(ops, Op{Kind: OpSet, Key: i, Value: step})
+			}
+		}
+
+		if err := Check(newMap, key, value, ops); err != nil {
+			t.Fatal(Check(newMap, key, value, Minimize(newMap, key, value, ops)))
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package hashmaptest_test
+
+import (
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap/hashmaptest"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	hashmaptest.Run(t, func() hashmap.Interface[int, int] { return hashmap.NewMap[int, int](0, 0.75) }, hashmaptest.Ints, hashmaptest.Ints)
+}
+
+func TestMap_Struct(t *testing.T) {
+	type point struct{ X, Y int }
+
+	hashmaptest.Run(t, func() hashmap.Interface[point, string] { return hashmap.NewMap[point, string](2, 0.75) },
+		func(i int) point { return point{i, -i} }, strconv.Itoa)
+}
+
+// forgetfulMap is a Map that ignores deletions once it holds more than three items.
+type forgetfulMap struct {
+	*hashmap.Map[int, int]
+}
+
+func (m forgetfulMap) Delete(key int) {
+	if m.Len() <= 3 {
+		m.Map.Delete(key)
+	}
+}
+
+func newForgetfulMap() hashmap.Interface[int, int] {
+	return forgetfulMap{hashmap.NewMap[int, int](2, 0.75)}
+}
+
+func TestCheck(t *testing.T) {
+	ops := []hashmaptest.Op{
+		{Kind: hashmaptest.OpSet, Key: 1, Value: 1},
+		{Kind: hashmaptest.OpDelete, Key: 1},
+		{Kind: hashmaptest.OpGet, Key: 1},
+	}
+
+	if err := hashmaptest.Check(newForgetfulMap, hashmaptest.Ints, hashmaptest.Ints, ops); err != nil {
+		t.Errorf("Expected no mismatch, got %v", err)
+	}
+}
+
+func TestCheck_Mismatch(t *testing.T) {
+	ops := hashmaptest.RandomOps(rand.New(rand.NewSource(1)), 200, 16)
+
+	err := hashmaptest.Check(newForgetfulMap, hashmaptest.Ints, hashmaptest.Ints, ops)
+
+	var mismatch *hashmaptest.Mismatch
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("Expected a mismatch, got %v", err)
+	}
+
+	minimal := hashmaptest.Minimize(newForgetfulMap, hashmaptest.Ints, hashmaptest.Ints, ops)
+
+	// Four distinct keys are needed before a deletion is ignored
+	if len(minimal) != 5 {
+		t.Errorf("Expected a minimal sequence of 5 operations, got %v", minimal)
+	}
+	if hashmaptest.Check(newForgetfulMap, hashmaptest.Ints, hashmaptest.Ints, minimal) == nil {
+		t.Errorf("Expected the minimal sequence to fail")
+	}
+}
+
+func TestCheck_Panic(t *testing.T) {
+	ops := []hashmaptest.Op{{Kind: hashmaptest.OpSet, Key: 1, Value: 1}}
+	panicking := func() hashmap.Interface[int, int] { return nil }
+
+	if err := hashmaptest.Check(panicking, hashmaptest.Ints, hashmaptest.Ints, ops); err == nil {
+		t.Errorf("Expected a panic to be reported as a mismatch")
+	}
+}
+
+func TestOp_String(t *testing.T) {
+	op := hashmaptest.Op{Kind: hashmaptest.OpSet, Key: 3, Value: 7}
+
+	if op.String() != "Set(k3, v7)" {
+		t.Errorf("Expected Set(k3, v7), got %s", op)
+	}
+}
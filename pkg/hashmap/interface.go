@@ -0,0 +1,22 @@
+package hashmap
+
+import "iter"
+
+// Interface is the behavior shared by the maps of this library,
+// so that code can accept any of them.
+//
+// Map satisfies it, as do the maps generated by cmd/hashmapgen.
+type Interface[K, V any] interface {
+	// Get returns the value associated with the key.
+	Get(key K) (value V, ok bool)
+	// Set associates the value with the key, replacing any previous value.
+	Set(key K, value V)
+	// Delete removes the key and its value.
+	Delete(key K)
+	// Len returns the number of items.
+	Len() int
+	// All returns an iterator over all items.
+	All() iter.Seq2[K, V]
+}
+
+var _ Interface[string, int] = (*Map[string, int])(nil)
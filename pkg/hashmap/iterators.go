@@ -1,5 +1,7 @@
 package hashmap
 
+import "iter"
+
 // Iter returns a channel that iterates over all items in the Map.
 func (ht *Map[K, V]) Iter() <-chan entry[K, V] {
 	ch := make(chan entry[K, V])
@@ -17,3 +19,19 @@ func (ht *Map[K, V]) Iter() <-chan entry[K, V] {
 
 	return ch
 }
+
+// All returns an iterator over all items in the Map.
+//
+// Unlike Iter, it walks the chains directly, without a goroutine, and stops as soon
+// as the loop breaks. The Map must not be modified during the iteration.
+func (ht *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, current := range ht.data {
+			for ; current != nil; current = current.Next {
+				if !yield(current.Key, current.Value) {
+					return
+				}
+			}
+		}
+	}
+}
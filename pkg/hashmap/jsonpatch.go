@@ -0,0 +1,129 @@
+package hashmap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidPatch is returned when parsing a JSON patch that cannot be turned into a Patch.
+var ErrInvalidPatch = errors.New("hashmap: invalid JSON patch")
+
+// jsonOperation is an operation of a JSON patch.
+type jsonOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// pointer returns the JSON pointer of a key, escaping "~" and "/" as RFC 6901 does.
+func pointer(key string) string {
+	return "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// JSONPatch renders a patch of a Map with string keys as a JSON patch, in the format of
+// RFC 6902, applying to the JSON object of the Map.
+// https://www.rfc-editor.org/rfc/rfc6902
+//
+// Added entries become "add" operations, and changed entries "replace" operations. Removed
+// and changed entries are preceded by a "test" operation holding their old value, so that
+// the patch can be checked before applying it, and parsed back with ParseJSONPatch.
+func JSONPatch[V any](p *Patch[string, V]) ([]byte, error) {
+	ops := make([]jsonOperation, 0, len(p.Added)+2*len(p.Removed)+2*len(p.Changed))
+
+	// add appends an operation, encoding its value
+	add := func(op, key string, value any) error {
+		o := jsonOperation{Op: op, Path: pointer(key)}
+		if op != "remove" {
+			b, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			o.Value = b
+		}
+		ops = append(ops, o)
+		return nil
+	}
+
+	for _, e := range p.Removed {
+		if err := add("test", e.Key, e.Value); err != nil {
+			return nil, err
+		}
+		_ = add("remove", e.Key, nil)
+	}
+	for _, e := range p.Added {
+		if err := add("add", e.Key, e.Value); err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range p.Changed {
+		if err := add("test", c.Key, c.Old); err != nil {
+			return nil, err
+		}
+		if err := add("replace", c.Key, c.New); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(ops)
+}
+
+// ParseJSONPatch parses a JSON patch rendered by JSONPatch back into a Patch.
+//
+// Each "remove" and "replace" operation must be preceded by a "test" operation on the same
+// path, holding the old value. Paths must point to top-level keys.
+func ParseJSONPatch[V any](data []byte) (*Patch[string, V], error) {
+	var ops []jsonOperation
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	p := &Patch[string, V]{}
+
+	for i := 0; i < len(ops); i++ {
+		op := ops[i]
+
+		if !strings.HasPrefix(op.Path, "/") || strings.Contains(op.Path[1:], "/") {
+			return nil, fmt.Errorf("%w: path %q is not a top-level key", ErrInvalidPatch, op.Path)
+		}
+		key := strings.NewReplacer("~1", "/", "~0", "~").Replace(op.Path[1:])
+
+		var value V
+		if op.Op != "remove" {
+			if err := json.Unmarshal(op.Value, &value); err != nil {
+				return nil, fmt.Errorf("%w: value of %q: %v", ErrInvalidPatch, op.Path, err)
+			}
+		}
+
+		switch op.Op {
+		case "add":
+			p.Added = append(p.Added, Entry[string, V]{Key: key, Value: value})
+			continue
+		case "test":
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q operation on %q", ErrInvalidPatch, op.Op, op.Path)
+		}
+
+		// A test operation holds the old value of the next operation
+		if i+1 == len(ops) || ops[i+1].Path != op.Path {
+			return nil, fmt.Errorf("%w: test of %q is not followed by an operation on it", ErrInvalidPatch, op.Path)
+		}
+		i++
+
+		switch next := ops[i]; next.Op {
+		case "remove":
+			p.Removed = append(p.Removed, Entry[string, V]{Key: key, Value: value})
+		case "replace":
+			var replacement V
+			if err := json.Unmarshal(next.Value, &replacement); err != nil {
+				return nil, fmt.Errorf("%w: value of %q: %v", ErrInvalidPatch, next.Path, err)
+			}
+			p.Changed = append(p.Changed, Change[string, V]{Key: key, Old: value, New: replacement})
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q operation on %q", ErrInvalidPatch, next.Op, next.Path)
+		}
+	}
+
+	return p, nil
+}
@@ -0,0 +1,59 @@
+package hashmap_test
+
+import (
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"testing"
+)
+
+func TestJSONPatch(t *testing.T) {
+	a := hashmap.NewMap[string, int](2, 0.75)
+	a.Set("a/b", 1)
+	a.Set("c~d", 2)
+
+	b := hashmap.NewMap[string, int](2, 0.75)
+	b.Set("a/b", 10)
+	b.Set("e", 3)
+
+	data, err := hashmap.JSONPatch(hashmap.Diff(a, b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[{"op":"test","path":"/c~0d","value":2},{"op":"remove","path":"/c~0d"},` +
+		`{"op":"add","path":"/e","value":3},` +
+		`{"op":"test","path":"/a~1b","value":1},{"op":"replace","path":"/a~1b","value":10}]`
+
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+
+	p, err := hashmap.ParseJSONPatch[int](data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Apply(p); err != nil {
+		t.Fatal(err)
+	}
+	if !a.Equal(b) {
+		t.Errorf("Expected %v, got %v", b, a)
+	}
+}
+
+func TestParseJSONPatch_Invalid(t *testing.T) {
+	patches := []string{
+		`{}`,
+		`[{"op":"remove","path":"/a"}]`,
+		`[{"op":"test","path":"/a","value":1},{"op":"remove","path":"/b"}]`,
+		`[{"op":"test","path":"/a","value":1}]`,
+		`[{"op":"add","path":"/a/b","value":1}]`,
+		`[{"op":"add","path":"/a","value":"one"}]`,
+		`[{"op":"move","path":"/a"}]`,
+	}
+
+	for _, patch := range patches {
+		if _, err := hashmap.ParseJSONPatch[int]([]byte(patch)); !errors.Is(err, hashmap.ErrInvalidPatch) {
+			t.Errorf("Expected ErrInvalidPatch for %s, got %v", patch, err)
+		}
+	}
+}
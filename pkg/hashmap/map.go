@@ -1,10 +1,8 @@
 package hashmap
 
 import (
-	"encoding/json"
 	"fmt"
 	"github.com/pietroagazzi/gohashlib/pkg/utils"
-	"hash/fnv"
 )
 
 const DefaultThreshold = 0.75
@@ -29,16 +27,13 @@ type Map[K, V any] struct {
 // It uses Marshal to convert the value to a byte slice, then hashes the byte slice using FNV-1a.
 // The hash is then modded by the size of the hash table to get the index.
 func (ht *Map[K, V]) Index(value K) (index uint32, err error) {
-	b, err := json.Marshal(value)
+	h, err := utils.Hash32(value)
 
 	if err != nil {
 		return 0, err
 	}
 
-	h := fnv.New32a()
-	_, err = h.Write(b)
-
-	return h.Sum32() % ht.size, err
+	return h % ht.size, nil
 }
 
 // NewMap returns a new Map with the given size and threshold.
@@ -117,6 +112,11 @@ func (ht *Map[K, V]) Set(key K, value V) {
 
 // Get returns the value associated with the key.
 func (ht *Map[K, V]) Get(key K) (value V, ok bool) {
+	// An empty Map with no slots has nothing to look up
+	if ht.size == 0 {
+		return value, false
+	}
+
 	index, _ := ht.Index(key)
 	current := ht.data[index]
 
@@ -133,6 +133,10 @@ func (ht *Map[K, V]) Get(key K) (value V, ok bool) {
 
 // Delete removes an item from the Map.
 func (ht *Map[K, V]) Delete(key K) {
+	if ht.size == 0 {
+		return
+	}
+
 	index, _ := ht.Index(key)
 	current := ht.data[index]
 
@@ -107,6 +107,16 @@ func TestMap_Get(t *testing.T) {
 	}
 }
 
+func TestMap_Get_SizeZero(t *testing.T) {
+	m := hashmap.NewMap[int, string](0, 0.75)
+
+	if _, ok := m.Get(1); ok {
+		t.Errorf("Expected to not find key 1 in an empty map")
+	}
+
+	m.Delete(1)
+}
+
 func TestMap_Delete(t *testing.T) {
 	t.Run("deleting a key", func(t *testing.T) {
 		m := hashmap.NewMap[int, string](2, 3)
@@ -0,0 +1,309 @@
+// Package iblt finds the differences between two sets held by different parties,
+// exchanging data proportional to the size of the difference rather than of the sets.
+package iblt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"github.com/pietroagazzi/gohashlib/pkg/utils"
+	"math"
+)
+
+// ErrIncompatible is returned when subtracting tables of different sizes.
+var ErrIncompatible = errors.New("iblt: incompatible tables")
+
+// ErrInvalidEncoding is returned when decoding bytes that are not an encoded table.
+var ErrInvalidEncoding = errors.New("iblt: invalid encoding")
+
+// ErrUndecodable is returned when the elements of a table cannot all be recovered,
+// usually because the table is too small for the difference it holds.
+var ErrUndecodable = errors.New("iblt: table cannot be decoded, the difference is too large")
+
+// hashes is the number of cells each element is added to.
+const hashes = 3
+
+// cell is a cell of a Table.
+type cell struct {
+	// count is the number of elements added minus the number removed
+	count int64
+	// keySum is the XOR of the encodings of the elements, zero-extended to the longest
+	keySum []byte
+	// hashSum is the XOR of the checksums of the elements
+	hashSum uint64
+}
+
+// pure reports whether the cell holds a single element, added or removed.
+func (c *cell) pure() bool {
+	return (c.count == 1 || c.count == -1) && c.hashSum == checksum(bytes.TrimRight(c.keySum, "\x00"))
+}
+
+// empty reports whether the cell holds no element.
+func (c *cell) empty() bool {
+	return c.count == 0 && c.hashSum == 0 && len(bytes.TrimRight(c.keySum, "\x00")) == 0
+}
+
+// checksum returns the checksum of the encoding of an element.
+func checksum(b []byte) uint64 {
+	return utils.Mix64(utils.Sum64(b))
+}
+
+// Table represents an invertible Bloom lookup table of the elements of a set.
+// https://en.wikipedia.org/wiki/Invertible_Bloom_filter
+//
+// Each element is added to one cell in each of three parts of the table, which hold the
+// count, the XOR of the encodings and the XOR of the checksums of their elements. Subtracting
+// the table of another set cancels the elements of both sets, leaving their difference,
+// which is recovered by repeatedly removing the elements of the cells holding a single one.
+//
+// Elements are encoded as hashmap.Map keys are, with encoding/json, and are decoded back
+// with json.Unmarshal, so they must survive a round trip through JSON.
+//
+// Decoding succeeds with high probability when the table has about 1.5 cells per element
+// of the difference, regardless of the size of the sets. See CellsFor.
+type Table[T any] struct {
+	cells []cell
+}
+
+// CellsFor returns the number of cells of a table that decodes a difference of
+// up to the given number of elements with high probability.
+func CellsFor(difference int) int {
+	cells := int(math.Ceil(1.5*float64(max(difference, 0)))) + 4*hashes
+	// Round up to the parts of the table
+	return (cells + hashes - 1) / hashes * hashes
+}
+
+// NewTable returns a new empty Table with the given number of cells,
+// rounded up to a multiple of 3.
+func NewTable[T any](cells int) *Table[T] {
+	cells = max(cells, hashes)
+	return &Table[T]{cells: make([]cell, (cells+hashes-1)/hashes*hashes)}
+}
+
+// Encode returns a Table of the elements of a set, with the given number of cells.
+// Both parties must encode their sets with the same number of cells.
+// It returns an error if an element cannot be encoded.
+func Encode[T any](s *set.Set[T], cells int) (*Table[T], error) {
+	t := NewTable[T](cells)
+
+	for value := range s.Elements() {
+		if err := t.Insert(value); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// Len returns the number of cells.
+func (t *Table[T]) Len() int { return len(t.cells) }
+
+// Insert adds an element to the table.
+// It returns an error if the element cannot be encoded.
+func (t *Table[T]) Insert(value T) error {
+	b, err := utils.Encode(value)
+	if err != nil {
+		return err
+	}
+
+	t.update(b, 1)
+	return nil
+}
+
+// Delete removes an element from the table. The element does not need to have been inserted:
+// deleting an element from an empty table leaves a table of a difference of one element.
+// It returns an error if the element cannot be encoded.
+func (t *Table[T]) Delete(value T) error {
+	b, err := utils.Encode(value)
+	if err != nil {
+		return err
+	}
+
+	t.update(b, -1)
+	return nil
+}
+
+// location returns the cell of an element, from the hash of its encoding, in the i-th part of the table.
+func (t *Table[T]) location(h uint64, i int) int {
+	part := uint64(len(t.cells) / hashes)
+	return i*int(part) + int(utils.Mix64(h+uint64(i)*0x9e3779b97f4a7c15)%part)
+}
+
+// update adds count times an encoded element to its cells.
+func (t *Table[T]) update(b []byte, count int64) {
+	h, sum := utils.Sum64(b), checksum(b)
+
+	for i := 0; i < hashes; i++ {
+		c := &t.cells[t.location(h, i)]
+
+		c.count += count
+		c.keySum = xor(c.keySum, b)
+		c.hashSum ^= sum
+	}
+}
+
+// xor returns the XOR of a and b, zero-extending the shorter, reusing a if long enough.
+func xor(a, b []byte) []byte {
+	if len(a) < len(b) {
+		a = append(a, make([]byte, len(b)-len(a))...)
+	}
+	for i := range b {
+		a[i] ^= b[i]
+	}
+	return a
+}
+
+// Copy returns a copy of the table.
+func (t *Table[T]) Copy() *Table[T] {
+	result := &Table[T]{cells: make([]cell, len(t.cells))}
+	for i, c := range t.cells {
+		result.cells[i] = cell{count: c.count, keySum: append([]byte(nil), c.keySum...), hashSum: c.hashSum}
+	}
+	return result
+}
+
+// Subtract returns the table of the difference of the set of the table and the set of another
+// table: its elements only in the first set are counted as added, and those only in the other
+// set as removed. Both tables must have the same number of cells.
+func (t *Table[T]) Subtract(other *Table[T]) (*Table[T], error) {
+	if len(t.cells) != len(other.cells) {
+		return nil, ErrIncompatible
+	}
+
+	result := t.Copy()
+	for i, c := range other.cells {
+		r := &result.cells[i]
+
+		r.count -= c.count
+		r.keySum = xor(r.keySum, c.keySum)
+		r.hashSum ^= c.hashSum
+	}
+
+	return result, nil
+}
+
+// Decode recovers the elements of the table: the elements added, and the elements removed.
+// On the result of Subtract, these are the elements only in the first set, and only in the
+// other set. The table is left unchanged.
+//
+// It returns ErrUndecodable, along with the elements recovered so far, when some elements
+// cannot be recovered, and an error if an element cannot be decoded from JSON.
+func (t *Table[T]) Decode() (added, removed *set.Set[T], err error) {
+	added = set.NewSet[T](8, hashmap.DefaultThreshold)
+	removed = set.NewSet[T](8, hashmap.DefaultThreshold)
+
+	work := t.Copy()
+
+	// Pure cells, which may stop being pure as elements are removed
+	queue := make([]int, 0, len(work.cells))
+	for i := range work.cells {
+		if work.cells[i].pure() {
+			queue = append(queue, i)
+		}
+	}
+
+	for len(queue) > 0 {
+		c := &work.cells[queue[0]]
+		queue = queue[1:]
+
+		if !c.pure() {
+			continue
+		}
+
+		b := bytes.Clone(bytes.TrimRight(c.keySum, "\x00"))
+		count := c.count
+
+		var value T
+		if err := json.Unmarshal(b, &value); err != nil {
+			return added, removed, err
+		}
+		if count > 0 {
+			added.Add(value)
+		} else {
+			removed.Add(value)
+		}
+
+		work.update(b, -count)
+
+		// Removing the element may have left its other cells pure
+		h := utils.Sum64(b)
+		for i := 0; i < hashes; i++ {
+			if j := work.location(h, i); work.cells[j].pure() {
+				queue = append(queue, j)
+			}
+		}
+	}
+
+	for i := range work.cells {
+		if !work.cells[i].empty() {
+			return added, removed, ErrUndecodable
+		}
+	}
+
+	return added, removed, nil
+}
+
+// ibltMagic starts every encoded Table.
+const ibltMagic = "IBL1"
+
+// MarshalBinary encodes the table into bytes, to be sent to the other party: a magic string
+// and the number of cells, then for each cell its count, its checksum, the length of its
+// key sum and the key sum. Integers are little-endian.
+func (t *Table[T]) MarshalBinary() ([]byte, error) {
+	out := []byte(ibltMagic)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(t.cells)))
+
+	for _, c := range t.cells {
+		keySum := bytes.TrimRight(c.keySum, "\x00")
+
+		out = binary.LittleEndian.AppendUint64(out, uint64(c.count))
+		out = binary.LittleEndian.AppendUint64(out, c.hashSum)
+		out = binary.LittleEndian.AppendUint32(out, uint32(len(keySum)))
+		out = append(out, keySum...)
+	}
+
+	return out, nil
+}
+
+// UnmarshalBinary decodes bytes produced by MarshalBinary, replacing the content of the table.
+func (t *Table[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < len(ibltMagic)+4 || string(data[:len(ibltMagic)]) != ibltMagic {
+		return ErrInvalidEncoding
+	}
+
+	n := binary.LittleEndian.Uint32(data[len(ibltMagic):])
+	data = data[len(ibltMagic)+4:]
+
+	if n == 0 || n%hashes != 0 || uint64(n)*20 > uint64(len(data)) {
+		return ErrInvalidEncoding
+	}
+
+	cells := make([]cell, n)
+	for i := range cells {
+		if len(data) < 20 {
+			return ErrInvalidEncoding
+		}
+
+		size := binary.LittleEndian.Uint32(data[16:])
+		if uint64(len(data)-20) < uint64(size) {
+			return ErrInvalidEncoding
+		}
+
+		cells[i] = cell{
+			count:   int64(binary.LittleEndian.Uint64(data)),
+			hashSum: binary.LittleEndian.Uint64(data[8:]),
+			keySum:  bytes.Clone(data[20 : 20+size]),
+		}
+		data = data[20+size:]
+	}
+
+	if len(data) != 0 {
+		return ErrInvalidEncoding
+	}
+
+	t.cells = cells
+	return nil
+}
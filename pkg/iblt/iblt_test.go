@@ -0,0 +1,168 @@
+package iblt_test
+
+import (
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/iblt"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"testing"
+)
+
+type record struct {
+	ID   int
+	Name string
+}
+
+// party is a service holding a set, reconciling it with another service.
+type party struct {
+	s *set.Set[record]
+}
+
+// send encodes the set of the party into the bytes sent to the other party.
+func (p party) send(t *testing.T, cells int) []byte {
+	t.Helper()
+
+	table, err := iblt.Encode(p.s, cells)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := table.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+// receive returns the elements only in the set of the party, and only in the set of the other party.
+func (p party) receive(t *testing.T, data []byte) (local, remote *set.Set[record], err error) {
+	t.Helper()
+
+	var other iblt.Table[record]
+	if err := other.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	table, err := iblt.Encode(p.s, other.Len())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	difference, err := table.Subtract(&other)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return difference.Decode()
+}
+
+// parties returns two parties sharing n records, each holding extra records of its own.
+func parties(n, onlyA, onlyB int) (a, b party) {
+	a = party{set.NewSet[record](8, hashmap.DefaultThreshold)}
+	b = party{set.NewSet[record](8, hashmap.DefaultThreshold)}
+
+	for i := 0; i < n; i++ {
+		a.s.Add(record{i, "shared"})
+		b.s.Add(record{i, "shared"})
+	}
+	for i := 0; i < onlyA; i++ {
+		a.s.Add(record{i, "a"})
+	}
+	for i := 0; i < onlyB; i++ {
+		b.s.Add(record{i, "b"})
+	}
+
+	return a, b
+}
+
+func TestTable_Reconcile(t *testing.T) {
+	a, b := parties(3000, 20, 10)
+	cells := iblt.CellsFor(30)
+
+	data := a.send(t, cells)
+
+	onlyB, onlyA, err := b.receive(t, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if onlyA.Len() != 20 || onlyB.Len() != 10 {
+		t.Fatalf("Expected 20 and 10 differing records, got %v and %v", onlyA, onlyB)
+	}
+	for value := range onlyA.Elements() {
+		if value.Name != "a" || !a.s.Contains(value) {
+			t.Errorf("Expected %v to be only in a", value)
+		}
+	}
+	for value := range onlyB.Elements() {
+		if value.Name != "b" || !b.s.Contains(value) {
+			t.Errorf("Expected %v to be only in b", value)
+		}
+	}
+
+	// The table does not grow with the sets
+	if large, _ := parties(6000, 20, 10); len(large.send(t, cells)) > 2*len(data) {
+		t.Errorf("Expected the encoding not to grow with the set")
+	}
+}
+
+func TestTable_Decode_Undecodable(t *testing.T) {
+	a, b := parties(100, 200, 200)
+
+	_, _, err := b.receive(t, a.send(t, iblt.CellsFor(10)))
+	if !errors.Is(err, iblt.ErrUndecodable) {
+		t.Errorf("Expected ErrUndecodable, got %v", err)
+	}
+}
+
+func TestTable_Decode_Equal(t *testing.T) {
+	a, b := parties(100, 0, 0)
+
+	onlyB, onlyA, err := b.receive(t, a.send(t, iblt.CellsFor(0)))
+	if err != nil || onlyA.Len() != 0 || onlyB.Len() != 0 {
+		t.Errorf("Expected no differences, got %v, %v and %v", onlyA, onlyB, err)
+	}
+}
+
+func TestTable_Delete(t *testing.T) {
+	table := iblt.NewTable[int](30)
+
+	_ = table.Insert(1)
+	_ = table.Insert(2)
+	_ = table.Delete(2)
+	_ = table.Delete(3)
+
+	added, removed, err := table.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added.Len() != 1 || !added.Contains(1) || removed.Len() != 1 || !removed.Contains(3) {
+		t.Errorf("Expected {1} and {3}, got %v and %v", added, removed)
+	}
+}
+
+func TestTable_Subtract_Incompatible(t *testing.T) {
+	if _, err := iblt.NewTable[int](30).Subtract(iblt.NewTable[int](60)); !errors.Is(err, iblt.ErrIncompatible) {
+		t.Errorf("Expected ErrIncompatible, got %v", err)
+	}
+}
+
+func TestTable_UnmarshalBinary(t *testing.T) {
+	table := iblt.NewTable[string](9)
+	_ = table.Insert("hello")
+
+	data, _ := table.MarshalBinary()
+
+	var decoded iblt.Table[string]
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if added, _, _ := decoded.Decode(); added.Len() != 1 || !added.Contains("hello") {
+		t.Errorf("Expected {hello}, got %v", added)
+	}
+
+	if err := decoded.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, iblt.ErrInvalidEncoding) {
+		t.Errorf("Expected ErrInvalidEncoding, got %v", err)
+	}
+}
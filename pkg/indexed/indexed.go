@@ -0,0 +1,256 @@
+// Package indexed provides a Map whose values can also be looked up by their attributes.
+package indexed
+
+import (
+	"errors"
+	"fmt"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"github.com/pietroagazzi/gohashlib/pkg/utils"
+	"iter"
+	"slices"
+)
+
+// ErrIndexExists is returned when adding an index with the name of an existing index.
+var ErrIndexExists = errors.New("indexed: index already exists")
+
+// ErrNoIndex is returned when using an index that does not exist.
+var ErrNoIndex = errors.New("indexed: no such index")
+
+// ErrDuplicate is returned when a value would share the key of a unique index with the value
+// of another key.
+var ErrDuplicate = errors.New("indexed: duplicate value in unique index")
+
+// index is a secondary index, mapping the index keys of the values to their keys.
+type index[K, V any] struct {
+	extract func(V) any
+	unique  bool
+	keys    *hashmap.Map[any, *set.Set[K]]
+	// indexKeys maps each key to the index key it is held under, so that it can be removed
+	// even if its value was changed in place since
+	indexKeys *hashmap.Map[K, any]
+}
+
+// owner returns a key other than key holding an index key in a unique index.
+func (ix *index[K, V]) owner(indexKey any, key K) (other K, found bool) {
+	keys, ok := ix.keys.Get(indexKey)
+	if !ok {
+		return other, false
+	}
+
+	for other := range keys.Elements() {
+		if !utils.Equaler(other, key) {
+			return other, true
+		}
+	}
+	return other, false
+}
+
+// add indexes a key under an index key.
+func (ix *index[K, V]) add(indexKey any, key K) {
+	keys, ok := ix.keys.Get(indexKey)
+	if !ok {
+		keys = set.NewSet[K](2, hashmap.DefaultThreshold)
+		ix.keys.Set(indexKey, keys)
+	}
+	keys.Add(key)
+	ix.indexKeys.Set(key, indexKey)
+}
+
+// remove removes a key from under the index key it was added with.
+func (ix *index[K, V]) remove(key K) {
+	indexKey, ok := ix.indexKeys.Get(key)
+	if !ok {
+		return
+	}
+	ix.indexKeys.Delete(key)
+
+	keys, ok := ix.keys.Get(indexKey)
+	if !ok {
+		return
+	}
+
+	keys.Remove(key)
+	if keys.Len() == 0 {
+		ix.keys.Delete(indexKey)
+	}
+}
+
+// clear removes all keys from the index.
+func (ix *index[K, V]) clear() {
+	ix.keys.Clear()
+	ix.indexKeys.Clear()
+}
+
+// IndexedMap represents a Map maintaining secondary indexes over its values.
+//
+// Each index is registered under a name with a function extracting an index key from a value,
+// such as an attribute. Indexes are updated on every Set and Delete, and Lookup returns the keys
+// of the values with an index key. A unique index holds at most one key per index key, and Set
+// refuses values that would break it.
+//
+// Index keys are hashed and compared as hashmap.Map keys are, so they can be structs, e.g. to
+// index several attributes together, but must be of the same type for equal index keys: the int
+// 1 and the int64 1 are different index keys.
+//
+// The index keys of a value are extracted when it is Set. A value changed in place, through
+// a pointer, keeps its former index keys until it is Set again.
+type IndexedMap[K, V any] struct {
+	m       *hashmap.Map[K, V]
+	indexes *hashmap.Map[string, *index[K, V]]
+	// names holds the names of the indexes, in the order they were added
+	names []string
+}
+
+// NewIndexedMap returns a new IndexedMap with the given size and threshold, and no index.
+func NewIndexedMap[K, V any](size uint32, threshold float32) *IndexedMap[K, V] {
+	return &IndexedMap[K, V]{
+		m:       hashmap.NewMap[K, V](size, threshold),
+		indexes: hashmap.NewMap[string, *index[K, V]](2, hashmap.DefaultThreshold),
+	}
+}
+
+// AddIndex adds an index, indexing the values already in the Map.
+// It returns an error if an index already has the name, or if the index is unique and
+// two values already share an index key.
+func (im *IndexedMap[K, V]) AddIndex(name string, extract func(V) any, unique bool) error {
+	if _, ok := im.indexes.Get(name); ok {
+		return fmt.Errorf("%w: %q", ErrIndexExists, name)
+	}
+
+	ix := &index[K, V]{
+		extract:   extract,
+		unique:    unique,
+		keys:      hashmap.NewMap[any, *set.Set[K]](im.m.Size(), hashmap.DefaultThreshold),
+		indexKeys: hashmap.NewMap[K, any](im.m.Size(), hashmap.DefaultThreshold),
+	}
+
+	for key, value := range im.m.All() {
+		indexKey := extract(value)
+		if other, found := ix.owner(indexKey, key); unique && found {
+			return fmt.Errorf("%w: %q holds %v for %v and %v", ErrDuplicate, name, indexKey, other, key)
+		}
+		ix.add(indexKey, key)
+	}
+
+	im.indexes.Set(name, ix)
+	im.names = append(im.names, name)
+
+	return nil
+}
+
+// RemoveIndex removes an index, and reports whether it existed.
+func (im *IndexedMap[K, V]) RemoveIndex(name string) bool {
+	if _, ok := im.indexes.Get(name); !ok {
+		return false
+	}
+
+	im.indexes.Delete(name)
+	im.names = slices.DeleteFunc(im.names, func(other string) bool { return other == name })
+
+	return true
+}
+
+// Indexes returns the names of the indexes, in the order they were added.
+func (im *IndexedMap[K, V]) Indexes() []string {
+	return slices.Clone(im.names)
+}
+
+// Set associates the value with the key, replacing any previous value, and updates the indexes.
+// It returns an ErrDuplicate, leaving the Map unchanged, if the value would share the key of a
+// unique index with the value of another key.
+func (im *IndexedMap[K, V]) Set(key K, value V) error {
+	indexKeys := make([]any, len(im.names))
+
+	for i, name := range im.names {
+		ix, _ := im.indexes.Get(name)
+		indexKeys[i] = ix.extract(value)
+
+		if other, found := ix.owner(indexKeys[i], key); ix.unique && found {
+			return fmt.Errorf("%w: %q holds %v for %v", ErrDuplicate, name, indexKeys[i], other)
+		}
+	}
+
+	im.unindex(key)
+	im.m.Set(key, value)
+
+	for i, name := range im.names {
+		ix, _ := im.indexes.Get(name)
+		ix.add(indexKeys[i], key)
+	}
+
+	return nil
+}
+
+// unindex removes a key from the indexes.
+func (im *IndexedMap[K, V]) unindex(key K) {
+	for _, ix := range im.indexes.All() {
+		ix.remove(key)
+	}
+}
+
+// Get returns the value associated with the key.
+func (im *IndexedMap[K, V]) Get(key K) (value V, ok bool) {
+	return im.m.Get(key)
+}
+
+// Delete removes the key and its value, and updates the indexes.
+func (im *IndexedMap[K, V]) Delete(key K) {
+	im.unindex(key)
+	im.m.Delete(key)
+}
+
+// Lookup returns the keys of the values whose index key in an index is indexKey.
+// It returns an error if the index does not exist.
+func (im *IndexedMap[K, V]) Lookup(name string, indexKey any) ([]K, error) {
+	ix, ok := im.indexes.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNoIndex, name)
+	}
+
+	keys, ok := ix.keys.Get(indexKey)
+	if !ok {
+		return []K{}, nil
+	}
+
+	return keys.ToSlice(), nil
+}
+
+// LookupValues returns the values whose index key in an index is indexKey.
+// It returns an error if the index does not exist.
+func (im *IndexedMap[K, V]) LookupValues(name string, indexKey any) ([]V, error) {
+	keys, err := im.Lookup(name, indexKey)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]V, len(keys))
+	for i, key := range keys {
+		values[i], _ = im.m.Get(key)
+	}
+
+	return values, nil
+}
+
+// Len returns the number of items.
+func (im *IndexedMap[K, V]) Len() int {
+	return im.m.Len()
+}
+
+// All returns an iterator over all items.
+func (im *IndexedMap[K, V]) All() iter.Seq2[K, V] {
+	return im.m.All()
+}
+
+// Clear removes all items from the Map and the indexes, keeping the indexes.
+func (im *IndexedMap[K, V]) Clear() {
+	im.m.Clear()
+	for _, ix := range im.indexes.All() {
+		ix.clear()
+	}
+}
+
+// String returns a string representation of the Map.
+func (im *IndexedMap[K, V]) String() string {
+	return im.m.String()
+}
@@ -0,0 +1,168 @@
+package indexed_test
+
+import (
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/indexed"
+	"slices"
+	"testing"
+)
+
+type employee struct {
+	Email string
+	Team  string
+	Level int
+}
+
+// newEmployees returns an IndexedMap of employees by ID, indexed by email and by team.
+func newEmployees(t *testing.T) *indexed.IndexedMap[int, employee] {
+	t.Helper()
+
+	m := indexed.NewIndexedMap[int, employee](8, 0.75)
+	if err := m.AddIndex("email", func(e employee) any { return e.Email }, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddIndex("team", func(e employee) any { return e.Team }, false); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = m.Set(1, employee{"ada@example.com", "core", 3})
+	_ = m.Set(2, employee{"alan@example.com", "core", 2})
+	_ = m.Set(3, employee{"grace@example.com", "infra", 3})
+
+	return m
+}
+
+// sorted returns the keys of a Lookup, sorted.
+func sorted(keys []int, err error) []int {
+	slices.Sort(keys)
+	return keys
+}
+
+func TestIndexedMap_Lookup(t *testing.T) {
+	m := newEmployees(t)
+
+	if keys := sorted(m.Lookup("team", "core")); !slices.Equal(keys, []int{1, 2}) {
+		t.Errorf("Expected [1 2], got %v", keys)
+	}
+	if keys, _ := m.Lookup("email", "grace@example.com"); !slices.Equal(keys, []int{3}) {
+		t.Errorf("Expected [3], got %v", keys)
+	}
+	if keys, err := m.Lookup("team", "sales"); len(keys) != 0 || err != nil {
+		t.Errorf("Expected no keys, got %v and %v", keys, err)
+	}
+	if _, err := m.Lookup("level", 3); !errors.Is(err, indexed.ErrNoIndex) {
+		t.Errorf("Expected ErrNoIndex, got %v", err)
+	}
+
+	values, _ := m.LookupValues("team", "infra")
+	if len(values) != 1 || values[0].Email != "grace@example.com" {
+		t.Errorf("Expected grace, got %v", values)
+	}
+}
+
+func TestIndexedMap_Set(t *testing.T) {
+	m := newEmployees(t)
+
+	// Moving alan to infra updates the team index
+	_ = m.Set(2, employee{"alan@example.com", "infra", 2})
+
+	if keys := sorted(m.Lookup("team", "infra")); !slices.Equal(keys, []int{2, 3}) {
+		t.Errorf("Expected [2 3], got %v", keys)
+	}
+	if keys, _ := m.Lookup("team", "core"); !slices.Equal(keys, []int{1}) {
+		t.Errorf("Expected [1], got %v", keys)
+	}
+
+	// A value cannot take the email of another key
+	err := m.Set(4, employee{"ada@example.com", "infra", 1})
+	if !errors.Is(err, indexed.ErrDuplicate) {
+		t.Errorf("Expected ErrDuplicate, got %v", err)
+	}
+	if _, ok := m.Get(4); ok || m.Len() != 3 {
+		t.Errorf("Expected the Map to be unchanged")
+	}
+	if keys := sorted(m.Lookup("team", "infra")); !slices.Equal(keys, []int{2, 3}) {
+		t.Errorf("Expected [2 3], got %v", keys)
+	}
+
+	// But a key can keep its own email
+	if err := m.Set(1, employee{"ada@example.com", "core", 4}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestIndexedMap_Delete(t *testing.T) {
+	m := newEmployees(t)
+
+	m.Delete(1)
+	m.Delete(10)
+
+	if keys, _ := m.Lookup("email", "ada@example.com"); len(keys) != 0 {
+		t.Errorf("Expected no keys, got %v", keys)
+	}
+	if err := m.Set(4, employee{"ada@example.com", "core", 1}); err != nil {
+		t.Errorf("Expected the email to be free, got %v", err)
+	}
+
+	m.Clear()
+
+	if keys, _ := m.Lookup("team", "core"); len(keys) != 0 || m.Len() != 0 {
+		t.Errorf("Expected no keys after Clear, got %v", keys)
+	}
+}
+
+func TestIndexedMap_AddIndex(t *testing.T) {
+	m := newEmployees(t)
+
+	if err := m.AddIndex("team", func(e employee) any { return e.Team }, false); !errors.Is(err, indexed.ErrIndexExists) {
+		t.Errorf("Expected ErrIndexExists, got %v", err)
+	}
+
+	// Existing values are indexed, and a unique index must hold for them
+	if err := m.AddIndex("level", func(e employee) any { return e.Level }, true); !errors.Is(err, indexed.ErrDuplicate) {
+		t.Errorf("Expected ErrDuplicate, got %v", err)
+	}
+
+	type teamLevel struct {
+		Team  string
+		Level int
+	}
+	_ = m.AddIndex("team-level", func(e employee) any { return teamLevel{e.Team, e.Level} }, false)
+
+	if keys, _ := m.Lookup("team-level", teamLevel{"core", 3}); !slices.Equal(keys, []int{1}) {
+		t.Errorf("Expected [1], got %v", keys)
+	}
+
+	if !m.RemoveIndex("team-level") || m.RemoveIndex("level") {
+		t.Errorf("Expected only team-level to be removed")
+	}
+	if names := m.Indexes(); !slices.Equal(names, []string{"email", "team"}) {
+		t.Errorf("Expected [email team], got %v", names)
+	}
+}
+
+func TestIndexedMap_Set_Pointer(t *testing.T) {
+	m := indexed.NewIndexedMap[int, *employee](8, 0.75)
+	_ = m.AddIndex("team", func(e *employee) any { return e.Team }, false)
+
+	ada := &employee{"ada@example.com", "core", 3}
+	_ = m.Set(1, ada)
+
+	// A value changed in place is re-indexed when it is Set again
+	ada.Team = "infra"
+	_ = m.Set(1, ada)
+
+	if keys, _ := m.Lookup("team", "core"); len(keys) != 0 {
+		t.Errorf("Expected no keys in core, got %v", keys)
+	}
+	if keys, _ := m.Lookup("team", "infra"); !slices.Equal(keys, []int{1}) {
+		t.Errorf("Expected [1], got %v", keys)
+	}
+
+	ada.Team = "sales"
+	m.Delete(1)
+
+	if keys, _ := m.Lookup("team", "infra"); len(keys) != 0 {
+		t.Errorf("Expected no keys in infra, got %v", keys)
+	}
+}
@@ -0,0 +1,33 @@
+// Package shrink minimizes failing sequences of operations found by randomized tests.
+package shrink
+
+// Minimize returns a subsequence of ops that still fails, such that removing any single
+// operation from it makes it pass.
+//
+// It removes chunks of decreasing size, starting from half of the sequence,
+// and keeps each removal after which the sequence still fails.
+func Minimize[O any](ops []O, fails func([]O) bool) []O {
+	for chunk := len(ops) / 2; chunk >= 1; chunk /= 2 {
+		for removed := true; removed; {
+			removed = false
+
+			for start := 0; start+chunk <= len(ops); {
+				candidate := append(append([]O(nil), ops[:start]...), ops[start+chunk:]...)
+
+				if fails(candidate) {
+					ops = candidate
+					removed = true
+				} else {
+					start += chunk
+				}
+			}
+
+			// Larger chunks are only tried once
+			if chunk > 1 {
+				break
+			}
+		}
+	}
+
+	return ops
+}
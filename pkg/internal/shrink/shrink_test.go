@@ -0,0 +1,32 @@
+package shrink_test
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/internal/shrink"
+	"slices"
+	"testing"
+)
+
+func TestMinimize(t *testing.T) {
+	ops := make([]int, 100)
+	for i := range ops {
+		ops[i] = i
+	}
+
+	// The sequence fails when it holds 17, then 42
+	fails := func(ops []int) bool {
+		i := slices.Index(ops, 17)
+		return i >= 0 && slices.Contains(ops[i:], 42)
+	}
+
+	minimal := shrink.Minimize(ops, fails)
+
+	if !slices.Equal(minimal, []int{17, 42}) {
+		t.Errorf("Expected [17 42], got %v", minimal)
+	}
+}
+
+func TestMinimize_Empty(t *testing.T) {
+	if minimal := shrink.Minimize(nil, func([]int) bool { return true }); len(minimal) != 0 {
+		t.Errorf("Expected empty sequence, got %v", minimal)
+	}
+}
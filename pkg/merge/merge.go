@@ -0,0 +1,104 @@
+// Package merge merges two versions of a Map or a Set edited independently from a common base.
+package merge
+
+import (
+	"errors"
+	"fmt"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/utils"
+)
+
+// ErrConflict is returned by the Fail strategy for every conflict.
+var ErrConflict = errors.New("merge: conflict")
+
+// Conflict is a key changed differently on both sides, such as a value changed to different
+// values, or changed on one side and deleted on the other.
+// The In fields tell whether the key is in each version.
+type Conflict[K, V any] struct {
+	Key                      K
+	Base, Ours, Theirs       V
+	InBase, InOurs, InTheirs bool
+}
+
+// String returns a string representation of the conflict.
+func (c Conflict[K, V]) String() string {
+	version := func(value V, ok bool) string {
+		if !ok {
+			return "(deleted)"
+		}
+		return fmt.Sprint(value)
+	}
+
+	return fmt.Sprintf("%v: base %s, ours %s, theirs %s",
+		c.Key, version(c.Base, c.InBase), version(c.Ours, c.InOurs), version(c.Theirs, c.InTheirs))
+}
+
+// Merge3 merges the changes made to base in ours and in theirs, returning a new Map.
+//
+// A key changed on a single side takes the value of that side, and a key changed identically
+// on both sides takes that value. Other keys are conflicts, resolved by the strategy: their
+// merged value is the one it returns, and the key is dropped if it returns false.
+// Values are compared with utils.Equaler. A nil base is an empty Map.
+//
+// It returns all the conflicts, along with the errors of the strategy joined. The keys whose
+// conflict the strategy failed to resolve keep the value of ours.
+func Merge3[K, V any](base, ours, theirs *hashmap.Map[K, V], strategy Strategy[K, V]) (*hashmap.Map[K, V], []Conflict[K, V], error) {
+	merged := hashmap.NewMap[K, V](max(ours.Size(), theirs.Size()), hashmap.DefaultThreshold)
+
+	var conflicts []Conflict[K, V]
+	var errs []error
+
+	resolve := func(key K) {
+		c := Conflict[K, V]{Key: key}
+		c.Base, c.InBase = get(base, key)
+		c.Ours, c.InOurs = ours.Get(key)
+		c.Theirs, c.InTheirs = theirs.Get(key)
+
+		value, ok := c.Ours, c.InOurs
+
+		switch {
+		case same(c.Ours, c.InOurs, c.Theirs, c.InTheirs):
+		case same(c.Base, c.InBase, c.Ours, c.InOurs):
+			value, ok = c.Theirs, c.InTheirs
+		case same(c.Base, c.InBase, c.Theirs, c.InTheirs):
+		default:
+			conflicts = append(conflicts, c)
+
+			resolved, keep, err := strategy(c)
+			if err != nil {
+				errs = append(errs, err)
+				break
+			}
+			value, ok = resolved, keep
+		}
+
+		if ok {
+			merged.Set(key, value)
+		}
+	}
+
+	for key := range ours.All() {
+		resolve(key)
+	}
+	for key := range theirs.All() {
+		if _, ok := ours.Get(key); !ok {
+			resolve(key)
+		}
+	}
+	// Keys deleted on both sides need no resolution
+
+	return merged, conflicts, errors.Join(errs...)
+}
+
+// get returns the value of a key in a Map, which may be nil.
+func get[K, V any](m *hashmap.Map[K, V], key K) (value V, ok bool) {
+	if m == nil {
+		return value, false
+	}
+	return m.Get(key)
+}
+
+// same reports whether two versions of a key are equal, both absent or both holding equal values.
+func same[V any](a V, aok bool, b V, bok bool) bool {
+	return aok == bok && (!aok || utils.Equaler(a, b))
+}
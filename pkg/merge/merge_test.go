@@ -0,0 +1,89 @@
+package merge_test
+
+import (
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/merge"
+	"testing"
+)
+
+// mapOf returns a Map of alternating keys and values.
+func mapOf(pairs ...any) *hashmap.Map[string, int] {
+	m := hashmap.NewMap[string, int](2, hashmap.DefaultThreshold)
+	for i := 0; i < len(pairs); i += 2 {
+		m.Set(pairs[i].(string), pairs[i+1].(int))
+	}
+	return m
+}
+
+func TestMerge3(t *testing.T) {
+	base := mapOf("same", 1, "ours", 1, "theirs", 1, "both", 1, "deleted", 1, "conflict", 1, "edit-delete", 1)
+	ours := mapOf("same", 1, "ours", 2, "theirs", 1, "both", 3, "conflict", 2, "added", 1, "edit-delete", 2)
+	theirs := mapOf("same", 1, "ours", 1, "theirs", 2, "both", 3, "conflict", 3, "added", 1, "new", 5)
+
+	merged, conflicts, err := merge.Merge3(base, ours, theirs, merge.Ours[string, int]())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := mapOf("same", 1, "ours", 2, "theirs", 2, "both", 3, "conflict", 2, "added", 1, "new", 5, "edit-delete", 2)
+	if !merged.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, merged)
+	}
+
+	if len(conflicts) != 2 {
+		t.Fatalf("Expected 2 conflicts, got %v", conflicts)
+	}
+	for _, c := range conflicts {
+		switch c.Key {
+		case "conflict":
+			if c.Base != 1 || c.Ours != 2 || c.Theirs != 3 {
+				t.Errorf("Expected 1, 2 and 3, got %v", c)
+			}
+		case "edit-delete":
+			if !c.InOurs || c.InTheirs {
+				t.Errorf("Expected edit-delete to be deleted by theirs, got %v", c)
+			}
+		default:
+			t.Errorf("Unexpected conflict %v", c)
+		}
+	}
+}
+
+func TestMerge3_Strategies(t *testing.T) {
+	base := mapOf("a", 1, "b", 1)
+	ours := mapOf("a", 2, "b", 2)
+	theirs := mapOf("a", 3)
+
+	merged, _, _ := merge.Merge3(base, ours, theirs, merge.Theirs[string, int]())
+	if expected := mapOf("a", 3); !merged.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, merged)
+	}
+
+	sum := func(c merge.Conflict[string, int]) (int, bool, error) {
+		return c.Ours + c.Theirs - c.Base, true, nil
+	}
+	merged, _, _ = merge.Merge3(base, ours, theirs, sum)
+	if expected := mapOf("a", 4, "b", 1); !merged.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, merged)
+	}
+
+	merged, conflicts, err := merge.Merge3(base, ours, theirs, merge.Fail[string, int]())
+	if !errors.Is(err, merge.ErrConflict) || len(conflicts) != 2 {
+		t.Errorf("Expected 2 conflicts and ErrConflict, got %v and %v", conflicts, err)
+	}
+	if !merged.Equal(ours) {
+		t.Errorf("Expected unresolved keys to keep ours, got %v", merged)
+	}
+}
+
+func TestMerge3_NilBase(t *testing.T) {
+	merged, conflicts, err := merge.Merge3(nil, mapOf("a", 1, "b", 2), mapOf("a", 1, "b", 3), merge.Fail[string, int]())
+
+	if len(conflicts) != 1 || conflicts[0].Key != "b" || conflicts[0].InBase || err == nil {
+		t.Errorf("Expected a conflict on b, got %v", conflicts)
+	}
+	if v, _ := merged.Get("a"); v != 1 {
+		t.Errorf("Expected a to be 1, got %d", v)
+	}
+}
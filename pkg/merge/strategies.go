@@ -0,0 +1,102 @@
+package merge
+
+import (
+	"fmt"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+)
+
+// Strategy resolves a conflict, returning the merged value of the key, or false to drop the key.
+// Any function with this signature is a custom strategy.
+type Strategy[K, V any] func(c Conflict[K, V]) (value V, ok bool, err error)
+
+// Ours returns a Strategy resolving conflicts with the version of ours.
+func Ours[K, V any]() Strategy[K, V] {
+	return func(c Conflict[K, V]) (V, bool, error) { return c.Ours, c.InOurs, nil }
+}
+
+// Theirs returns a Strategy resolving conflicts with the version of theirs.
+func Theirs[K, V any]() Strategy[K, V] {
+	return func(c Conflict[K, V]) (V, bool, error) { return c.Theirs, c.InTheirs, nil }
+}
+
+// Fail returns a Strategy resolving no conflict, returning an ErrConflict for each.
+func Fail[K, V any]() Strategy[K, V] {
+	return func(c Conflict[K, V]) (value V, ok bool, err error) {
+		return value, false, fmt.Errorf("%w: %v", ErrConflict, c)
+	}
+}
+
+// Maps returns a Strategy merging conflicting Map values recursively, with Merge3 and the
+// strategy of their own values. A missing version is an empty Map, and the key is dropped
+// if it was deleted on a side and its merged Map is empty.
+func Maps[K, K2, V2 any](strategy Strategy[K2, V2]) Strategy[K, *hashmap.Map[K2, V2]] {
+	return func(c Conflict[K, *hashmap.Map[K2, V2]]) (*hashmap.Map[K2, V2], bool, error) {
+		empty := hashmap.NewMap[K2, V2](0, hashmap.DefaultThreshold)
+
+		base, ours, theirs := c.Base, c.Ours, c.Theirs
+		if !c.InBase {
+			base = nil
+		}
+		if !c.InOurs {
+			ours = empty
+		}
+		if !c.InTheirs {
+			theirs = empty
+		}
+
+		merged, _, err := Merge3(base, ours, theirs, strategy)
+		if err != nil {
+			return nil, false, fmt.Errorf("key %v: %w", c.Key, err)
+		}
+
+		return merged, merged.Len() > 0 || c.InOurs && c.InTheirs, nil
+	}
+}
+
+// Sets returns a Strategy merging conflicting Set values with Merge3Sets, which never
+// conflicts. A missing version is an empty Set, and the key is dropped if it was deleted
+// on a side and its merged Set is empty.
+func Sets[K, T any]() Strategy[K, *set.Set[T]] {
+	return func(c Conflict[K, *set.Set[T]]) (*set.Set[T], bool, error) {
+		empty := set.NewSet[T](0, hashmap.DefaultThreshold)
+
+		base, ours, theirs := c.Base, c.Ours, c.Theirs
+		if !c.InBase {
+			base = nil
+		}
+		if !c.InOurs {
+			ours = empty
+		}
+		if !c.InTheirs {
+			theirs = empty
+		}
+
+		merged := Merge3Sets(base, ours, theirs)
+		return merged, merged.Len() > 0 || c.InOurs && c.InTheirs, nil
+	}
+}
+
+// Merge3Sets merges the changes made to base in ours and in theirs, returning a new Set.
+//
+// An element is in the merged Set if it is in both sides, or if it was added on a side,
+// unless it was removed on the other. Sets never conflict. A nil base is an empty Set.
+func Merge3Sets[T any](base, ours, theirs *set.Set[T]) *set.Set[T] {
+	merged := set.NewSet[T](max(ours.Size(), theirs.Size()), hashmap.DefaultThreshold)
+	inBase := func(value T) bool { return base != nil && base.Contains(value) }
+
+	for value := range ours.Elements() {
+		// Kept unless theirs removed it
+		if theirs.Contains(value) || !inBase(value) {
+			merged.Add(value)
+		}
+	}
+	for value := range theirs.Elements() {
+		// Added by theirs, as ours does not have it
+		if !ours.Contains(value) && !inBase(value) {
+			merged.Add(value)
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,97 @@
+package merge_test
+
+import (
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/merge"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"testing"
+)
+
+// setOf returns a Set of values.
+func setOf(values ...string) *set.Set[string] {
+	s := set.NewSet[string](2, hashmap.DefaultThreshold)
+	s.Add(values...)
+	return s
+}
+
+func TestMerge3Sets(t *testing.T) {
+	base := setOf("a", "b", "c")
+	ours := setOf("a", "b", "d")
+	theirs := setOf("b", "c", "e")
+
+	// ours removed c and added d, theirs removed a and added e
+	merged := merge.Merge3Sets(base, ours, theirs)
+	if expected := setOf("b", "d", "e"); !merged.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, merged)
+	}
+
+	if merged := merge.Merge3Sets(nil, ours, theirs); merged.Len() != 5 {
+		t.Errorf("Expected the union without a base, got %v", merged)
+	}
+}
+
+func TestSets(t *testing.T) {
+	roles := func(pairs map[string]*set.Set[string]) *hashmap.Map[string, *set.Set[string]] {
+		m := hashmap.NewMap[string, *set.Set[string]](2, hashmap.DefaultThreshold)
+		for k, v := range pairs {
+			m.Set(k, v)
+		}
+		return m
+	}
+
+	base := roles(map[string]*set.Set[string]{"alice": setOf("read"), "bob": setOf("read")})
+	ours := roles(map[string]*set.Set[string]{"alice": setOf("read", "write"), "bob": setOf("read", "admin")})
+	theirs := roles(map[string]*set.Set[string]{"alice": setOf("write", "deploy")})
+
+	merged, conflicts, err := merge.Merge3(base, ours, theirs, merge.Sets[string, string]())
+	if err != nil || len(conflicts) != 2 {
+		t.Fatalf("Expected 2 resolved conflicts, got %v and %v", conflicts, err)
+	}
+
+	if alice, _ := merged.Get("alice"); !alice.Equal(setOf("write", "deploy")) {
+		t.Errorf("Expected alice to write and deploy, got %v", alice)
+	}
+	// theirs deleted bob, but ours granted admin
+	if bob, _ := merged.Get("bob"); !bob.Equal(setOf("admin")) {
+		t.Errorf("Expected bob to be admin, got %v", bob)
+	}
+}
+
+func TestMaps(t *testing.T) {
+	type config = hashmap.Map[string, *hashmap.Map[string, int]]
+
+	services := func(api, worker *hashmap.Map[string, int]) *config {
+		m := hashmap.NewMap[string, *hashmap.Map[string, int]](2, hashmap.DefaultThreshold)
+		m.Set("api", api)
+		if worker != nil {
+			m.Set("worker", worker)
+		}
+		return m
+	}
+
+	base := services(mapOf("cpu", 1, "memory", 1), mapOf("cpu", 1))
+	ours := services(mapOf("cpu", 2, "memory", 1), nil)
+	theirs := services(mapOf("cpu", 1, "memory", 4), mapOf("cpu", 1))
+
+	merged, conflicts, err := merge.Merge3(base, ours, theirs, merge.Maps[string](merge.Fail[string, int]()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 1 || conflicts[0].Key != "api" {
+		t.Errorf("Expected a conflict on api, got %v", conflicts)
+	}
+
+	if api, _ := merged.Get("api"); !api.Equal(mapOf("cpu", 2, "memory", 4)) {
+		t.Errorf("Expected cpu 2 and memory 4, got %v", api)
+	}
+	if _, ok := merged.Get("worker"); ok {
+		t.Errorf("Expected worker to be deleted")
+	}
+
+	// Nested conflicts are resolved by the strategy of the values
+	theirs.Set("api", mapOf("cpu", 3, "memory", 1))
+	if _, _, err := merge.Merge3(base, ours, theirs, merge.Maps[string](merge.Fail[string, int]())); !errors.Is(err, merge.ErrConflict) {
+		t.Errorf("Expected ErrConflict, got %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+// Package merkle finds the keys that differ between two Maps by comparing few hashes,
+// e.g. to synchronize replicas of a Map held by different processes.
+package merkle
+
+import (
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/utils"
+	"slices"
+)
+
+// ErrIncompatible is returned when comparing trees of different depths.
+var ErrIncompatible = errors.New("merkle: incompatible trees")
+
+// MaxDepth is the maximum depth of a tree, which has 2^depth buckets.
+const MaxDepth = 24
+
+// Entry is a key in a bucket, along with the hash of its entry.
+type Entry[K any] struct {
+	Key  K
+	Hash uint64
+}
+
+// Tree represents a Merkle tree over the entries of a Map.
+// https://en.wikipedia.org/wiki/Merkle_tree
+//
+// Keys are spread over 2^depth buckets by their hash. Each leaf holds the sum of
+// utils.HashEntry of the entries of a bucket, and each inner node the sum of its
+// children, so the root is the Digest of the Map.
+//
+// Two trees are compared from the root down, only descending into the nodes whose
+// hashes differ: when few entries differ, this exchanges O(log n) hashes instead of
+// comparing the n entries.
+//
+// Nodes are numbered as in a binary heap: the root is 1, and the children of node i
+// are 2i and 2i + 1. The leaf of bucket b is node 2^depth + b.
+type Tree[K any] struct {
+	depth int
+	// nodes holds the hash of each node, indexed by its number
+	nodes []uint64
+	// buckets holds the entries of each bucket
+	buckets [][]Entry[K]
+}
+
+// NewTree returns the Merkle tree of the entries of a Map, with 2^depth buckets.
+// The depth is capped at MaxDepth.
+// It returns an error if a key or a value cannot be encoded.
+func NewTree[K, V any](m *hashmap.Map[K, V], depth int) (*Tree[K], error) {
+	depth = min(max(depth, 0), MaxDepth)
+
+	t := &Tree[K]{
+		depth:   depth,
+		nodes:   make([]uint64, 2<<depth),
+		buckets: make([][]Entry[K], 1<<depth),
+	}
+
+	for key, value := range m.All() {
+		b, err := t.Bucket(key)
+		if err != nil {
+			return nil, err
+		}
+
+		h, err := utils.HashEntry(key, value)
+		if err != nil {
+			return nil, err
+		}
+
+		t.buckets[b] = append(t.buckets[b], Entry[K]{Key: key, Hash: h})
+		t.nodes[1<<depth+b] += h
+	}
+
+	for i := 1<<depth - 1; i >= 1; i-- {
+		t.nodes[i] = t.nodes[2*i] + t.nodes[2*i+1]
+	}
+
+	return t, nil
+}
+
+// Depth returns the depth of the tree.
+func (t *Tree[K]) Depth() int { return t.depth }
+
+// Root returns the hash of the root, equal to the Digest of the Map.
+func (t *Tree[K]) Root() uint64 { return t.nodes[1] }
+
+// Node returns the hash of a node, or 0 if there is no such node.
+func (t *Tree[K]) Node(i int) uint64 {
+	if i < 1 || i >= len(t.nodes) {
+		return 0
+	}
+	return t.nodes[i]
+}
+
+// Bucket returns the bucket of a key, from the high bits of its hash.
+// It returns an error if the key cannot be encoded.
+func (t *Tree[K]) Bucket(key K) (int, error) {
+	h, err := utils.Hash64(key)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(utils.Mix64(h) >> (64 - t.depth)), nil
+}
+
+// Entries returns the entries of a bucket.
+func (t *Tree[K]) Entries(bucket int) []Entry[K] {
+	if bucket < 0 || bucket >= len(t.buckets) {
+		return nil
+	}
+	return t.buckets[bucket]
+}
+
+// DifferingBuckets returns the buckets whose entries differ from those of another tree,
+// walking both trees from the root down.
+//
+// The hashes of the other tree are read with fetch, which is given the numbers of the nodes
+// of a level and returns their hashes. When the trees are in different processes, each call
+// to fetch is a round trip, and depth + 1 calls are made at most.
+func (t *Tree[K]) DifferingBuckets(depth int, fetch func(nodes []int) ([]uint64, error)) ([]int, error) {
+	if depth != t.depth {
+		return nil, ErrIncompatible
+	}
+
+	level := []int{1}
+
+	for len(level) > 0 {
+		hashes, err := fetch(level)
+		if err != nil {
+			return nil, err
+		}
+		if len(hashes) != len(level) {
+			return nil, ErrIncompatible
+		}
+
+		var differing []int
+		for i, node := range level {
+			if t.nodes[node] != hashes[i] {
+				differing = append(differing, node)
+			}
+		}
+
+		// The differing nodes are leaves
+		if len(differing) == 0 || differing[0] >= 1<<t.depth {
+			buckets := make([]int, len(differing))
+			for i, node := range differing {
+				buckets[i] = node - 1<<t.depth
+			}
+			return buckets, nil
+		}
+
+		level = level[:0]
+		for _, node := range differing {
+			level = append(level, 2*node, 2*node+1)
+		}
+	}
+
+	return nil, nil
+}
+
+// DiffEntries returns the keys of a bucket whose entries differ from the entries of the
+// same bucket of another tree: the keys added, removed or whose value changed.
+func (t *Tree[K]) DiffEntries(bucket int, other []Entry[K]) []K {
+	var result []K
+
+	local := t.Entries(bucket)
+	for _, e := range local {
+		i := slices.IndexFunc(other, func(o Entry[K]) bool { return utils.Equaler(o.Key, e.Key) })
+		if i < 0 || other[i].Hash != e.Hash {
+			result = append(result, e.Key)
+		}
+	}
+
+	for _, o := range other {
+		if !slices.ContainsFunc(local, func(e Entry[K]) bool { return utils.Equaler(e.Key, o.Key) }) {
+			result = append(result, o.Key)
+		}
+	}
+
+	return result
+}
+
+// Diff returns the keys whose entries differ between the tree and another tree of the same
+// depth: the keys added, removed or whose value changed.
+func (t *Tree[K]) Diff(other *Tree[K]) ([]K, error) {
+	buckets, err := t.DifferingBuckets(other.depth, func(nodes []int) ([]uint64, error) {
+		hashes := make([]uint64, len(nodes))
+		for i, node := range nodes {
+			hashes[i] = other.Node(node)
+		}
+		return hashes, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []K
+	for _, b := range buckets {
+		result = append(result, t.DiffEntries(b, other.Entries(b))...)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,133 @@
+package merkle_test
+
+import (
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/merkle"
+	"slices"
+	"sort"
+	"testing"
+)
+
+type key struct {
+	Shard int
+	Name  string
+}
+
+// replicas returns two Maps of n entries, differing by a changed, an added and a removed key.
+func replicas(n int) (a, b *hashmap.Map[key, int]) {
+	a = hashmap.NewMap[key, int](16, hashmap.DefaultThreshold)
+	b = hashmap.NewMap[key, int](16, hashmap.DefaultThreshold)
+
+	for i := 0; i < n; i++ {
+		a.Set(key{i % 7, "k"}, i)
+		b.Set(key{i % 7, "k"}, i)
+		a.Set(key{i, "v"}, i)
+		b.Set(key{i, "v"}, i)
+	}
+
+	b.Set(key{10, "v"}, -1)
+	b.Set(key{-1, "added"}, 0)
+	b.Delete(key{20, "v"})
+
+	return a, b
+}
+
+func TestTree_Diff(t *testing.T) {
+	a, b := replicas(5000)
+
+	ta, err := merkle.NewTree(a, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tb, _ := merkle.NewTree(b, 10)
+
+	keys, err := ta.Diff(tb)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Shard < keys[j].Shard })
+	expected := []key{{-1, "added"}, {10, "v"}, {20, "v"}}
+
+	if !slices.Equal(keys, expected) {
+		t.Errorf("Expected %v, got %v", expected, keys)
+	}
+}
+
+func TestTree_Root(t *testing.T) {
+	a, b := replicas(100)
+
+	ta, _ := merkle.NewTree(a, 4)
+	digest, _ := a.Digest()
+
+	if ta.Root() != digest {
+		t.Errorf("Expected root %d to be the digest of the Map, got %d", digest, ta.Root())
+	}
+
+	// Equal Maps have no differing keys
+	tb, _ := merkle.NewTree(a, 4)
+	if keys, _ := ta.Diff(tb); len(keys) != 0 {
+		t.Errorf("Expected no differing keys, got %v", keys)
+	}
+
+	// A single bucket compares all the entries
+	ta, _ = merkle.NewTree(a, 0)
+	tb, _ = merkle.NewTree(b, 0)
+	if keys, _ := ta.Diff(tb); len(keys) != 3 {
+		t.Errorf("Expected 3 differing keys, got %v", keys)
+	}
+}
+
+func TestTree_DifferingBuckets(t *testing.T) {
+	a, b := replicas(5000)
+
+	ta, _ := merkle.NewTree(a, 12)
+	tb, _ := merkle.NewTree(b, 12)
+
+	// Count the hashes sent by the other side
+	calls, hashes := 0, 0
+	buckets, err := ta.DifferingBuckets(tb.Depth(), func(nodes []int) ([]uint64, error) {
+		calls++
+		hashes += len(nodes)
+
+		result := make([]uint64, len(nodes))
+		for i, node := range nodes {
+			result[i] = tb.Node(node)
+		}
+		return result, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(buckets) < 1 || len(buckets) > 3 {
+		t.Errorf("Expected 1 to 3 differing buckets, got %v", buckets)
+	}
+	// 3 paths of 12 levels, instead of 10000 entries
+	if calls != 13 || hashes > 1+12*6 {
+		t.Errorf("Expected 13 calls and at most 73 hashes, got %d and %d", calls, hashes)
+	}
+
+	for _, bucket := range buckets {
+		if len(ta.DiffEntries(bucket, tb.Entries(bucket))) == 0 {
+			t.Errorf("Expected differing keys in bucket %d", bucket)
+		}
+	}
+}
+
+func TestTree_Diff_Incompatible(t *testing.T) {
+	a, b := replicas(10)
+
+	ta, _ := merkle.NewTree(a, 4)
+	tb, _ := merkle.NewTree(b, 5)
+
+	if _, err := ta.Diff(tb); !errors.Is(err, merkle.ErrIncompatible) {
+		t.Errorf("Expected ErrIncompatible, got %v", err)
+	}
+
+	fetch := func(nodes []int) ([]uint64, error) { return nil, nil }
+	if _, err := ta.DifferingBuckets(4, fetch); !errors.Is(err, merkle.ErrIncompatible) {
+		t.Errorf("Expected ErrIncompatible, got %v", err)
+	}
+}
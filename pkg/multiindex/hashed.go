@@ -0,0 +1,118 @@
+package multiindex
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"github.com/pietroagazzi/gohashlib/pkg/utils"
+	"iter"
+)
+
+// Hashed represents a hashed index of a Container, finding the records with an index key
+// in constant time. Index keys are hashed and compared as hashmap.Map keys are.
+type Hashed[K, V, I any] struct {
+	c       *Container[K, V]
+	n       string
+	extract func(V) I
+	unique  bool
+	// keys maps each index key to the primary keys of its records
+	keys *hashmap.Map[I, *set.Set[K]]
+}
+
+// AddHashed adds a hashed index to a Container, indexing the records by the index key returned
+// by extract, and indexes the records already in it. A unique index holds at most one record
+// per index key. It returns an error if an index already has the name, or if the index is
+// unique and two records already share an index key.
+func AddHashed[K, V, I any](c *Container[K, V], name string, extract func(V) I, unique bool) (*Hashed[K, V, I], error) {
+	h := &Hashed[K, V, I]{
+		c:       c,
+		n:       name,
+		extract: extract,
+		unique:  unique,
+		keys:    hashmap.NewMap[I, *set.Set[K]](c.records.Size(), hashmap.DefaultThreshold),
+	}
+
+	if err := c.addIndex(h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (h *Hashed[K, V, I]) name() string { return h.n }
+
+func (h *Hashed[K, V, I]) conflict(key K, value V) (other K, found bool) {
+	if !h.unique {
+		return other, false
+	}
+
+	keys, ok := h.keys.Get(h.extract(value))
+	if !ok {
+		return other, false
+	}
+	for other := range keys.Elements() {
+		if !utils.Equaler(other, key) {
+			return other, true
+		}
+	}
+	return other, false
+}
+
+func (h *Hashed[K, V, I]) insert(key K, value V) {
+	ik := h.extract(value)
+
+	keys, ok := h.keys.Get(ik)
+	if !ok {
+		keys = set.NewSet[K](2, hashmap.DefaultThreshold)
+		h.keys.Set(ik, keys)
+	}
+	keys.Add(key)
+}
+
+func (h *Hashed[K, V, I]) remove(key K, value V) {
+	ik := h.extract(value)
+
+	keys, ok := h.keys.Get(ik)
+	if !ok {
+		return
+	}
+
+	keys.Remove(key)
+	if keys.Len() == 0 {
+		h.keys.Delete(ik)
+	}
+}
+
+func (h *Hashed[K, V, I]) clear() { h.keys.Clear() }
+
+// Find returns a record with an index key, the only one in a unique index.
+func (h *Hashed[K, V, I]) Find(ik I) (key K, value V, ok bool) {
+	for key, value := range h.Lookup(ik) {
+		return key, value, true
+	}
+	return key, value, false
+}
+
+// Lookup returns an iterator over the records with an index key.
+func (h *Hashed[K, V, I]) Lookup(ik I) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		keys, ok := h.keys.Get(ik)
+		if !ok {
+			return
+		}
+
+		for key := range keys.Elements() {
+			value, _ := h.c.records.Get(key)
+			if !yield(key, value) {
+				return
+			}
+		}
+	}
+}
+
+// Count returns the number of records with an index key.
+func (h *Hashed[K, V, I]) Count(ik I) int {
+	keys, ok := h.keys.Get(ik)
+	if !ok {
+		return 0
+	}
+	return keys.Len()
+}
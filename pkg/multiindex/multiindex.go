@@ -0,0 +1,193 @@
+// Package multiindex provides a collection of records indexed in several ways at once,
+// in the spirit of Boost.MultiIndex: by hash, uniquely or not, and in order, with range queries.
+package multiindex
+
+import (
+	"errors"
+	"fmt"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"iter"
+)
+
+// ErrIndexExists is returned when adding an index with the name of an existing index.
+var ErrIndexExists = errors.New("multiindex: index already exists")
+
+// ErrDuplicate is returned when a record would share the key of a unique index with another record.
+var ErrDuplicate = errors.New("multiindex: duplicate value in unique index")
+
+// ErrNotFound is returned when modifying a record that does not exist.
+var ErrNotFound = errors.New("multiindex: record not found")
+
+// index is the behavior the Container needs from its indexes.
+type index[K, V any] interface {
+	// name returns the name of the index
+	name() string
+	// conflict returns the record, other than the record with key, that a record would share
+	// the key of a unique index with
+	conflict(key K, value V) (other K, found bool)
+	// insert indexes a record
+	insert(key K, value V)
+	// remove removes a record from the index
+	remove(key K, value V)
+	// clear removes all records from the index
+	clear()
+}
+
+// Container represents a collection of records, stored in a hashmap.Map by their primary key,
+// and maintained in any number of secondary indexes.
+//
+// Indexes are added with AddHashed and AddOrdered, which return typed handles to query them.
+// Every change to the records goes through Insert, Set, Modify and Delete, which keep all the
+// indexes consistent: a change that would break a unique index is refused as a whole.
+//
+// Records must not be changed while iterating over the Container or one of its indexes.
+type Container[K, V any] struct {
+	records *hashmap.Map[K, V]
+	indexes []index[K, V]
+}
+
+// New returns a new empty Container with the given size and threshold, and no index.
+func New[K, V any](size uint32, threshold float32) *Container[K, V] {
+	return &Container[K, V]{records: hashmap.NewMap[K, V](size, threshold)}
+}
+
+// addIndex indexes the records in a new index, and adds it.
+func (c *Container[K, V]) addIndex(ix index[K, V]) error {
+	for _, other := range c.indexes {
+		if other.name() == ix.name() {
+			return fmt.Errorf("%w: %q", ErrIndexExists, ix.name())
+		}
+	}
+
+	for key, value := range c.records.All() {
+		if other, found := ix.conflict(key, value); found {
+			return fmt.Errorf("%w: %q holds %v and %v", ErrDuplicate, ix.name(), other, key)
+		}
+		ix.insert(key, value)
+	}
+
+	c.indexes = append(c.indexes, ix)
+	return nil
+}
+
+// Get returns the record with a primary key.
+func (c *Container[K, V]) Get(key K) (value V, ok bool) {
+	return c.records.Get(key)
+}
+
+// Len returns the number of records.
+func (c *Container[K, V]) Len() int {
+	return c.records.Len()
+}
+
+// All returns an iterator over the records, in no particular order.
+func (c *Container[K, V]) All() iter.Seq2[K, V] {
+	return c.records.All()
+}
+
+// Insert adds a record, and reports whether it was added: it is not if a record already
+// has the primary key. It returns an ErrDuplicate if the record would break a unique index.
+func (c *Container[K, V]) Insert(key K, value V) (bool, error) {
+	if _, ok := c.records.Get(key); ok {
+		return false, nil
+	}
+
+	if err := c.check(key, value); err != nil {
+		return false, err
+	}
+
+	c.records.Set(key, value)
+	c.index(key, value)
+
+	return true, nil
+}
+
+// Set adds a record, replacing the record with the same primary key.
+// It returns an ErrDuplicate, leaving the Container unchanged, if the record would break
+// a unique index.
+func (c *Container[K, V]) Set(key K, value V) error {
+	if err := c.check(key, value); err != nil {
+		return err
+	}
+
+	if old, existed := c.records.Get(key); existed {
+		c.unindex(key, old)
+	}
+
+	c.records.Set(key, value)
+	c.index(key, value)
+
+	return nil
+}
+
+// Modify changes the record with a primary key in place, calling f with a copy of it,
+// and re-indexes it. It returns an ErrNotFound if there is no such record, and an ErrDuplicate,
+// leaving the Container unchanged, if the changed record would break a unique index.
+//
+// The record is copied as an assignment does, so f must replace, rather than change,
+// the slices and maps the record holds.
+func (c *Container[K, V]) Modify(key K, f func(*V)) error {
+	old, ok := c.records.Get(key)
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrNotFound, key)
+	}
+
+	value := old
+	f(&value)
+
+	if err := c.check(key, value); err != nil {
+		return err
+	}
+
+	c.unindex(key, old)
+	c.records.Set(key, value)
+	c.index(key, value)
+
+	return nil
+}
+
+// Delete removes the record with a primary key, and reports whether it existed.
+func (c *Container[K, V]) Delete(key K) bool {
+	old, ok := c.records.Get(key)
+	if !ok {
+		return false
+	}
+
+	c.unindex(key, old)
+	c.records.Delete(key)
+
+	return true
+}
+
+// Clear removes all records, keeping the indexes.
+func (c *Container[K, V]) Clear() {
+	c.records.Clear()
+	for _, ix := range c.indexes {
+		ix.clear()
+	}
+}
+
+// check returns an ErrDuplicate if a record would break a unique index.
+// The record with the same primary key, which the record replaces, is not a conflict.
+func (c *Container[K, V]) check(key K, value V) error {
+	for _, ix := range c.indexes {
+		if other, found := ix.conflict(key, value); found {
+			return fmt.Errorf("%w: %q already holds %v", ErrDuplicate, ix.name(), other)
+		}
+	}
+	return nil
+}
+
+// index adds a record to all the indexes.
+func (c *Container[K, V]) index(key K, value V) {
+	for _, ix := range c.indexes {
+		ix.insert(key, value)
+	}
+}
+
+// unindex removes a record from all the indexes.
+func (c *Container[K, V]) unindex(key K, value V) {
+	for _, ix := range c.indexes {
+		ix.remove(key, value)
+	}
+}
@@ -0,0 +1,236 @@
+package multiindex_test
+
+import (
+	"cmp"
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/multiindex"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+type order struct {
+	Customer string
+	Ref      string
+	Total    int
+}
+
+// orders is a Container of orders by ID, with a unique index by reference,
+// an index by customer and an ordered index by total.
+type orders struct {
+	*multiindex.Container[int, order]
+	byRef      *multiindex.Hashed[int, order, string]
+	byCustomer *multiindex.Hashed[int, order, string]
+	byTotal    *multiindex.Ordered[int, order, int]
+}
+
+func newOrders(t *testing.T) orders {
+	t.Helper()
+
+	c := multiindex.New[int, order](8, 0.75)
+	o := orders{Container: c}
+
+	var err error
+	if o.byRef, err = multiindex.AddHashed(c, "ref", func(o order) string { return o.Ref }, true); err != nil {
+		t.Fatal(err)
+	}
+	if o.byCustomer, err = multiindex.AddHashed(c, "customer", func(o order) string { return o.Customer }, false); err != nil {
+		t.Fatal(err)
+	}
+	if o.byTotal, err = multiindex.AddOrdered(c, "total", func(o order) int { return o.Total }, cmp.Compare[int], false); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = c.Insert(1, order{"ada", "A-1", 30})
+	_, _ = c.Insert(2, order{"alan", "A-2", 10})
+	_, _ = c.Insert(3, order{"ada", "A-3", 20})
+
+	return o
+}
+
+// keys collects the keys of an iterator.
+func keys[V any](seq func(func(int, V) bool)) []int {
+	var result []int
+	for key := range seq {
+		result = append(result, key)
+	}
+	return result
+}
+
+func TestContainer_Insert(t *testing.T) {
+	o := newOrders(t)
+
+	if added, err := o.Insert(1, order{"grace", "A-4", 5}); added || err != nil {
+		t.Errorf("Expected existing key not to be added, got %v and %v", added, err)
+	}
+	if _, err := o.Insert(4, order{"grace", "A-1", 5}); !errors.Is(err, multiindex.ErrDuplicate) {
+		t.Errorf("Expected ErrDuplicate, got %v", err)
+	}
+	if o.Len() != 3 || o.byCustomer.Count("grace") != 0 {
+		t.Errorf("Expected the Container to be unchanged")
+	}
+
+	if key, value, ok := o.byRef.Find("A-3"); !ok || key != 3 || value.Total != 20 {
+		t.Errorf("Expected order 3, got %d and %v", key, value)
+	}
+	if ks := keys(o.byCustomer.Lookup("ada")); len(ks) != 2 {
+		t.Errorf("Expected 2 orders of ada, got %v", ks)
+	}
+}
+
+func TestContainer_Modify(t *testing.T) {
+	o := newOrders(t)
+
+	err := o.Modify(2, func(v *order) {
+		v.Customer = "ada"
+		v.Total = 40
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if o.byCustomer.Count("ada") != 3 || o.byCustomer.Count("alan") != 0 {
+		t.Errorf("Expected all orders to be ada's")
+	}
+	if ks := keys(o.byTotal.All()); !slices.Equal(ks, []int{3, 1, 2}) {
+		t.Errorf("Expected [3 1 2], got %v", ks)
+	}
+
+	// A change breaking the unique index is rolled back
+	err = o.Modify(2, func(v *order) {
+		v.Ref = "A-1"
+		v.Total = 0
+	})
+	if !errors.Is(err, multiindex.ErrDuplicate) {
+		t.Errorf("Expected ErrDuplicate, got %v", err)
+	}
+	if v, _ := o.Get(2); v.Ref != "A-2" || v.Total != 40 {
+		t.Errorf("Expected order 2 to be unchanged, got %v", v)
+	}
+	if key, _, _ := o.byRef.Find("A-2"); key != 2 {
+		t.Errorf("Expected A-2 to still be indexed")
+	}
+	if key, _, _ := o.byTotal.Max(); key != 2 {
+		t.Errorf("Expected order 2 to still be the largest")
+	}
+
+	if err := o.Modify(10, func(*order) {}); !errors.Is(err, multiindex.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestContainer_Set(t *testing.T) {
+	o := newOrders(t)
+
+	// A record keeps its own unique index key
+	if err := o.Set(1, order{"ada", "A-1", 5}); err != nil {
+		t.Fatal(err)
+	}
+	if key, _, _ := o.byTotal.Min(); key != 1 {
+		t.Errorf("Expected order 1 to be the smallest, got %d", key)
+	}
+
+	if err := o.Set(4, order{"grace", "A-2", 5}); !errors.Is(err, multiindex.ErrDuplicate) {
+		t.Errorf("Expected ErrDuplicate, got %v", err)
+	}
+
+	if !o.Delete(1) || o.Delete(1) {
+		t.Errorf("Expected order 1 to be deleted once")
+	}
+	if _, _, ok := o.byRef.Find("A-1"); ok || o.byTotal.Count() != 2 {
+		t.Errorf("Expected order 1 to be removed from the indexes")
+	}
+
+	o.Clear()
+	if _, _, ok := o.byTotal.Min(); ok || o.byCustomer.Count("ada") != 0 {
+		t.Errorf("Expected empty indexes after Clear")
+	}
+}
+
+func TestAddHashed_Existing(t *testing.T) {
+	o := newOrders(t)
+
+	if _, err := multiindex.AddHashed(o.Container, "ref", func(o order) string { return o.Ref }, false); !errors.Is(err, multiindex.ErrIndexExists) {
+		t.Errorf("Expected ErrIndexExists, got %v", err)
+	}
+	if _, err := multiindex.AddHashed(o.Container, "unique-customer", func(o order) string { return o.Customer }, true); !errors.Is(err, multiindex.ErrDuplicate) {
+		t.Errorf("Expected ErrDuplicate, got %v", err)
+	}
+
+	// A failed index is not added
+	if _, err := multiindex.AddHashed(o.Container, "unique-customer", func(o order) string { return o.Customer }, false); err != nil {
+		t.Errorf("Expected the name to be free, got %v", err)
+	}
+}
+
+func TestContainer_Random(t *testing.T) {
+	o := newOrders(t)
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 2000; i++ {
+		key := rng.Intn(50)
+		value := order{Customer: string(rune('a' + rng.Intn(5))), Ref: string(rune('A' + rng.Intn(60))), Total: rng.Intn(100)}
+
+		switch rng.Intn(4) {
+		case 0:
+			_, _ = o.Insert(key, value)
+		case 1:
+			_ = o.Set(key, value)
+		case 2:
+			_ = o.Modify(key, func(v *order) { v.Total = value.Total; v.Ref = value.Ref })
+		case 3:
+			o.Delete(key)
+		}
+	}
+
+	// Every index agrees with the records
+	count, previous := 0, -1
+	for key, value := range o.byTotal.All() {
+		if record, _ := o.Get(key); record != value || value.Total < previous {
+			t.Fatalf("Expected sorted records matching the Container, got %d: %v", key, value)
+		}
+		previous = value.Total
+		count++
+
+		if found, _, _ := o.byRef.Find(value.Ref); found != key {
+			t.Fatalf("Expected %s to be indexed for %d, got %d", value.Ref, key, found)
+		}
+	}
+
+	customers := 0
+	for _, c := range "abcde" {
+		customers += o.byCustomer.Count(string(c))
+	}
+	if count != o.Len() || customers != o.Len() {
+		t.Errorf("Expected %d records in every index, got %d and %d", o.Len(), count, customers)
+	}
+}
+
+func TestContainer_Refused(t *testing.T) {
+	o := newOrders(t)
+	_ = o.Set(2, order{"alan", "A-2", 30})
+	_ = o.Set(3, order{"ada", "A-3", 30})
+
+	before := keys(o.byTotal.All())
+
+	if err := o.Set(1, order{"ada", "A-2", 30}); !errors.Is(err, multiindex.ErrDuplicate) {
+		t.Errorf("Expected ErrDuplicate, got %v", err)
+	}
+	if err := o.Modify(1, func(v *order) { v.Ref = "A-3" }); !errors.Is(err, multiindex.ErrDuplicate) {
+		t.Errorf("Expected ErrDuplicate, got %v", err)
+	}
+
+	// A panicking change leaves the record indexed
+	func() {
+		defer func() { _ = recover() }()
+		_ = o.Modify(1, func(v *order) { panic("boom") })
+	}()
+
+	// The records keep their order among equal index keys
+	if after := keys(o.byTotal.All()); !slices.Equal(before, after) {
+		t.Errorf("Expected %v, got %v", before, after)
+	}
+	if key, _, _ := o.byRef.Find("A-1"); key != 1 {
+		t.Errorf("Expected A-1 to still be indexed")
+	}
+}
@@ -0,0 +1,165 @@
+package multiindex
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/utils"
+	"iter"
+	"slices"
+)
+
+// orderedEntry is a record of an ordered index.
+type orderedEntry[K, V, I any] struct {
+	ik    I
+	key   K
+	value V
+}
+
+// Ordered represents an ordered index of a Container, keeping the records sorted by their
+// index key, to iterate over them in order and query ranges of index keys.
+//
+// Records are kept in a sorted slice: queries take O(log n) time to find their first record,
+// and changes O(n) time to shift the records after them. Records with equal index keys are
+// kept in the order they were indexed.
+type Ordered[K, V, I any] struct {
+	n       string
+	extract func(V) I
+	compare func(a, b I) int
+	unique  bool
+	entries []orderedEntry[K, V, I]
+}
+
+// AddOrdered adds an ordered index to a Container, sorting the records by the index key returned
+// by extract, compared with compare, such as cmp.Compare. It indexes the records already in the
+// Container. A unique index holds at most one record per index key. It returns an error if an index
+// already has the name, or if the index is unique and two records already share an index key.
+func AddOrdered[K, V, I any](c *Container[K, V], name string, extract func(V) I, compare func(a, b I) int, unique bool) (*Ordered[K, V, I], error) {
+	o := &Ordered[K, V, I]{n: name, extract: extract, compare: compare, unique: unique}
+
+	if err := c.addIndex(o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// lowerBound returns the position of the first record whose index key is not before ik.
+func (o *Ordered[K, V, I]) lowerBound(ik I) int {
+	i, _ := slices.BinarySearchFunc(o.entries, ik, func(e orderedEntry[K, V, I], ik I) int {
+		return o.compare(e.ik, ik)
+	})
+	return i
+}
+
+// upperBound returns the position of the first record whose index key is after ik.
+func (o *Ordered[K, V, I]) upperBound(ik I) int {
+	i, _ := slices.BinarySearchFunc(o.entries, ik, func(e orderedEntry[K, V, I], ik I) int {
+		if o.compare(e.ik, ik) <= 0 {
+			return -1
+		}
+		return 1
+	})
+	return i
+}
+
+func (o *Ordered[K, V, I]) name() string { return o.n }
+
+func (o *Ordered[K, V, I]) conflict(key K, value V) (other K, found bool) {
+	if !o.unique {
+		return other, false
+	}
+
+	ik := o.extract(value)
+	for i := o.lowerBound(ik); i < len(o.entries) && o.compare(o.entries[i].ik, ik) == 0; i++ {
+		if !utils.Equaler(o.entries[i].key, key) {
+			return o.entries[i].key, true
+		}
+	}
+	return other, false
+}
+
+func (o *Ordered[K, V, I]) insert(key K, value V) {
+	ik := o.extract(value)
+	o.entries = slices.Insert(o.entries, o.upperBound(ik), orderedEntry[K, V, I]{ik: ik, key: key, value: value})
+}
+
+func (o *Ordered[K, V, I]) remove(key K, value V) {
+	ik := o.extract(value)
+
+	for i := o.lowerBound(ik); i < len(o.entries) && o.compare(o.entries[i].ik, ik) == 0; i++ {
+		if utils.Equaler(o.entries[i].key, key) {
+			o.entries = slices.Delete(o.entries, i, i+1)
+			return
+		}
+	}
+}
+
+func (o *Ordered[K, V, I]) clear() { o.entries = nil }
+
+// iterate returns an iterator over the records between the positions returned by bounds.
+// The positions are found when iterating, so that they match the records at that time.
+func (o *Ordered[K, V, I]) iterate(bounds func() (from, to int)) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		from, to := bounds()
+		for _, e := range o.entries[from:max(from, to)] {
+			if !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the records, sorted by index key.
+func (o *Ordered[K, V, I]) All() iter.Seq2[K, V] {
+	return o.iterate(func() (int, int) { return 0, len(o.entries) })
+}
+
+// Backward returns an iterator over the records, sorted by index key in reverse order.
+func (o *Ordered[K, V, I]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for i := len(o.entries) - 1; i >= 0; i-- {
+			if !yield(o.entries[i].key, o.entries[i].value) {
+				return
+			}
+		}
+	}
+}
+
+// Lookup returns an iterator over the records with an index key.
+func (o *Ordered[K, V, I]) Lookup(ik I) iter.Seq2[K, V] {
+	return o.iterate(func() (int, int) { return o.lowerBound(ik), o.upperBound(ik) })
+}
+
+// Range returns an iterator over the records whose index key is in [from, to), sorted by index key.
+func (o *Ordered[K, V, I]) Range(from, to I) iter.Seq2[K, V] {
+	return o.iterate(func() (int, int) { return o.lowerBound(from), o.lowerBound(to) })
+}
+
+// From returns an iterator over the records whose index key is not before from, sorted by index key.
+func (o *Ordered[K, V, I]) From(from I) iter.Seq2[K, V] {
+	return o.iterate(func() (int, int) { return o.lowerBound(from), len(o.entries) })
+}
+
+// Until returns an iterator over the records whose index key is before to, sorted by index key.
+func (o *Ordered[K, V, I]) Until(to I) iter.Seq2[K, V] {
+	return o.iterate(func() (int, int) { return 0, o.lowerBound(to) })
+}
+
+// Min returns the record with the smallest index key.
+func (o *Ordered[K, V, I]) Min() (key K, value V, ok bool) {
+	if len(o.entries) == 0 {
+		return key, value, false
+	}
+	return o.entries[0].key, o.entries[0].value, true
+}
+
+// Max returns the record with the largest index key.
+func (o *Ordered[K, V, I]) Max() (key K, value V, ok bool) {
+	if len(o.entries) == 0 {
+		return key, value, false
+	}
+	e := o.entries[len(o.entries)-1]
+	return e.key, e.value, true
+}
+
+// Count returns the number of records.
+func (o *Ordered[K, V, I]) Count() int {
+	return len(o.entries)
+}
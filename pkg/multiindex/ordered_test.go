@@ -0,0 +1,113 @@
+package multiindex_test
+
+import (
+	"cmp"
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/multiindex"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type event struct {
+	Day  int
+	Name string
+}
+
+func TestOrdered_Range(t *testing.T) {
+	c := multiindex.New[string, event](8, 0.75)
+	byDay, _ := multiindex.AddOrdered(c, "day", func(e event) int { return e.Day }, cmp.Compare[int], false)
+
+	for _, e := range []event{{5, "e"}, {1, "a"}, {3, "c1"}, {3, "c2"}, {9, "i"}, {7, "g"}} {
+		_, _ = c.Insert(e.Name, e)
+	}
+
+	names := func(seq func(func(string, event) bool)) []string {
+		var result []string
+		for name := range seq {
+			result = append(result, name)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		got      []string
+		expected []string
+	}{
+		{"All", names(byDay.All()), []string{"a", "c1", "c2", "e", "g", "i"}},
+		{"Backward", names(byDay.Backward()), []string{"i", "g", "e", "c2", "c1", "a"}},
+		{"Range", names(byDay.Range(3, 7)), []string{"c1", "c2", "e"}},
+		{"Range gap", names(byDay.Range(4, 5)), nil},
+		{"Range reversed", names(byDay.Range(7, 3)), nil},
+		{"From", names(byDay.From(6)), []string{"g", "i"}},
+		{"Until", names(byDay.Until(3)), []string{"a"}},
+		{"Lookup", names(byDay.Lookup(3)), []string{"c1", "c2"}},
+	}
+
+	for _, test := range tests {
+		if !slices.Equal(test.got, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, test.got)
+		}
+	}
+
+	if key, _, _ := byDay.Min(); key != "a" {
+		t.Errorf("Expected a to be first, got %s", key)
+	}
+	if key, _, _ := byDay.Max(); key != "i" {
+		t.Errorf("Expected i to be last, got %s", key)
+	}
+	if byDay.Count() != 6 {
+		t.Errorf("Expected 6 records, got %d", byDay.Count())
+	}
+}
+
+func TestOrdered_Unique(t *testing.T) {
+	c := multiindex.New[int, event](8, 0.75)
+	_, _ = c.Insert(1, event{1, "Launch"})
+	_, _ = c.Insert(2, event{2, "launch"})
+
+	// Names compared without case collide
+	_, err := multiindex.AddOrdered(c, "name", func(e event) string { return e.Name }, strings.Compare, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := multiindex.AddOrdered(c, "folded", func(e event) string { return e.Name }, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	}, true); !errors.Is(err, multiindex.ErrDuplicate) {
+		t.Errorf("Expected ErrDuplicate, got %v", err)
+	}
+
+	if _, err := c.Insert(3, event{3, "Launch"}); !errors.Is(err, multiindex.ErrDuplicate) {
+		t.Errorf("Expected ErrDuplicate, got %v", err)
+	}
+}
+
+func TestOrdered_Stale(t *testing.T) {
+	c := multiindex.New[int, event](8, 0.75)
+	byDay, _ := multiindex.AddOrdered(c, "day", func(e event) int { return e.Day }, cmp.Compare[int], false)
+
+	for day := 1; day <= 5; day++ {
+		_, _ = c.Insert(day, event{day, "e"})
+	}
+
+	// An iterator covers the records at the time it runs
+	from := byDay.From(3)
+	c.Delete(4)
+	c.Delete(3)
+	c.Delete(2)
+
+	var got []int
+	for key := range from {
+		got = append(got, key)
+	}
+	if !slices.Equal(got, []int{5}) {
+		t.Errorf("Expected [5], got %v", got)
+	}
+
+	all := byDay.Range(1, 6)
+	c.Clear()
+	for key := range all {
+		t.Errorf("Expected no records, got %d", key)
+	}
+}
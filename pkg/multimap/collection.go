@@ -0,0 +1,79 @@
+package multimap
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"github.com/pietroagazzi/gohashlib/pkg/utils"
+)
+
+// collection holds the values associated with a single key of a MultiMap.
+type collection[V any] interface {
+	// Add adds a value to the collection.
+	Add(value V)
+	// Remove removes a value from the collection, returning true if it was found.
+	Remove(value V) bool
+	// Contains checks if the collection contains a value.
+	Contains(value V) bool
+	// Len returns the number of values in the collection.
+	Len() int
+	// Values returns a slice of all values in the collection.
+	Values() []V
+}
+
+// list is a collection keeping every value, in insertion order.
+type list[V any] []V
+
+func (l *list[V]) Add(value V) {
+	*l = append(*l, value)
+}
+
+// Remove removes the first occurrence of the value.
+func (l *list[V]) Remove(value V) bool {
+	for i, v := range *l {
+		if utils.Equaler(v, value) {
+			*l = append((*l)[:i], (*l)[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (l *list[V]) Contains(value V) bool {
+	for _, v := range *l {
+		if utils.Equaler(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *list[V]) Len() int { return len(*l) }
+
+func (l *list[V]) Values() []V {
+	return append([]V(nil), *l...)
+}
+
+// setCollection is a collection keeping distinct values only.
+type setCollection[V any] struct {
+	s *set.Set[V]
+}
+
+func newSetCollection[V any]() collection[V] {
+	return setCollection[V]{s: set.NewSet[V](2, hashmap.DefaultThreshold)}
+}
+
+func (c setCollection[V]) Add(value V) { c.s.Add(value) }
+
+func (c setCollection[V]) Remove(value V) bool {
+	if !c.s.Contains(value) {
+		return false
+	}
+	c.s.Remove(value)
+	return true
+}
+
+func (c setCollection[V]) Contains(value V) bool { return c.s.Contains(value) }
+
+func (c setCollection[V]) Len() int { return c.s.Len() }
+
+func (c setCollection[V]) Values() []V { return c.s.ToSlice() }
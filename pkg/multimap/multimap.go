@@ -0,0 +1,162 @@
+package multimap
+
+import (
+	"fmt"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+)
+
+// MultiMap represents a Map associating each key with a collection of values.
+//
+// The values of a key are kept either in a list, which preserves duplicates and
+// insertion order, or in a set.Set, which keeps distinct values only.
+// Keys without values are removed from the MultiMap.
+type MultiMap[K, V any] struct {
+	m *hashmap.Map[K, collection[V]]
+
+	// newCollection creates the collection of a key on its first value
+	newCollection func() collection[V]
+}
+
+// NewListMultiMap returns a new MultiMap with the given size and threshold,
+// keeping the values of each key in a list.
+func NewListMultiMap[K, V any](size uint32, threshold float32) *MultiMap[K, V] {
+	return &MultiMap[K, V]{
+		m:             hashmap.NewMap[K, collection[V]](size, threshold),
+		newCollection: func() collection[V] { return new(list[V]) },
+	}
+}
+
+// NewSetMultiMap returns a new MultiMap with the given size and threshold,
+// keeping the values of each key in a set.Set.
+func NewSetMultiMap[K, V any](size uint32, threshold float32) *MultiMap[K, V] {
+	return &MultiMap[K, V]{
+		m:             hashmap.NewMap[K, collection[V]](size, threshold),
+		newCollection: newSetCollection[V],
+	}
+}
+
+// Put adds values to the collection associated with the key.
+func (mm *MultiMap[K, V]) Put(key K, values ...V) {
+	if len(values) == 0 {
+		return
+	}
+
+	c, ok := mm.m.Get(key)
+	if !ok {
+		c = mm.newCollection()
+		mm.m.Set(key, c)
+	}
+
+	for _, v := range values {
+		c.Add(v)
+	}
+}
+
+// GetAll returns a slice of all values associated with the key.
+// The slice is a copy, so it can be modified without affecting the MultiMap.
+func (mm *MultiMap[K, V]) GetAll(key K) []V {
+	c, ok := mm.m.Get(key)
+	if !ok {
+		return nil
+	}
+
+	return c.Values()
+}
+
+// Contains checks if the value is associated with the key.
+func (mm *MultiMap[K, V]) Contains(key K, value V) bool {
+	c, ok := mm.m.Get(key)
+	return ok && c.Contains(value)
+}
+
+// ContainsKey checks if the key has at least one value.
+func (mm *MultiMap[K, V]) ContainsKey(key K) bool {
+	_, ok := mm.m.Get(key)
+	return ok
+}
+
+// Remove removes a value associated with the key.
+// With list collections, only the first occurrence of the value is removed.
+func (mm *MultiMap[K, V]) Remove(key K, value V) {
+	c, ok := mm.m.Get(key)
+	if !ok || !c.Remove(value) {
+		return
+	}
+
+	if c.Len() == 0 {
+		mm.m.Delete(key)
+	}
+}
+
+// RemoveAll removes the key and all its values.
+func (mm *MultiMap[K, V]) RemoveAll(key K) {
+	mm.m.Delete(key)
+}
+
+// Count returns the number of values associated with the key.
+func (mm *MultiMap[K, V]) Count(key K) int {
+	c, ok := mm.m.Get(key)
+	if !ok {
+		return 0
+	}
+
+	return c.Len()
+}
+
+// Len returns the number of (key, value) pairs in the MultiMap.
+func (mm *MultiMap[K, V]) Len() int {
+	count := 0
+
+	for i := range mm.m.Iter() {
+		count += i.Value.Len()
+	}
+
+	return count
+}
+
+// KeyLen returns the number of distinct keys in the MultiMap.
+func (mm *MultiMap[K, V]) KeyLen() int {
+	return mm.m.Len()
+}
+
+// Keys return a slice of all distinct keys in the MultiMap.
+func (mm *MultiMap[K, V]) Keys() []K {
+	return mm.m.Keys()
+}
+
+// Clear removes all keys and values from the MultiMap.
+func (mm *MultiMap[K, V]) Clear() {
+	mm.m.Clear()
+}
+
+// Iter returns a channel that iterates over all (key, value) pairs in the MultiMap.
+func (mm *MultiMap[K, V]) Iter() <-chan hashmap.Entry[K, V] {
+	ch := make(chan hashmap.Entry[K, V])
+
+	go func() {
+		for i := range mm.m.Iter() {
+			for _, v := range i.Value.Values() {
+				ch <- hashmap.Entry[K, V]{Key: i.Key, Value: v}
+			}
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+// String returns a string representation of the MultiMap.
+func (mm *MultiMap[K, V]) String() string {
+	str := "{"
+
+	for i := range mm.m.Iter() {
+		str += fmt.Sprintf("%v: %v, ", i.Key, i.Value.Values())
+	}
+
+	// Remove the trailing comma and space
+	if len(str) > 1 {
+		str = str[:len(str)-2]
+	}
+
+	return str + "}"
+}
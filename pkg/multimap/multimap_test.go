@@ -0,0 +1,130 @@
+package multimap_test
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/multimap"
+	"testing"
+)
+
+type point struct {
+	X, Y int
+}
+
+func TestMultiMap_Put(t *testing.T) {
+	mm := multimap.NewListMultiMap[point, string](2, 0.75)
+	mm.Put(point{1, 2}, "a", "b")
+	mm.Put(point{1, 2}, "a")
+	mm.Put(point{3, 4}, "c")
+
+	if mm.Len() != 4 {
+		t.Errorf("Expected length to be 4, got %d", mm.Len())
+	}
+	if mm.KeyLen() != 2 {
+		t.Errorf("Expected key length to be 2, got %d", mm.KeyLen())
+	}
+
+	values := mm.GetAll(point{1, 2})
+	if len(values) != 3 || values[0] != "a" || values[1] != "b" || values[2] != "a" {
+		t.Errorf("Expected values to be [a b a], got %v", values)
+	}
+}
+
+func TestMultiMap_Put_Set(t *testing.T) {
+	mm := multimap.NewSetMultiMap[point, string](2, 0.75)
+	mm.Put(point{1, 2}, "a", "b")
+	mm.Put(point{1, 2}, "a")
+
+	if mm.Count(point{1, 2}) != 2 {
+		t.Errorf("Expected count to be 2, got %d", mm.Count(point{1, 2}))
+	}
+	if !mm.Contains(point{1, 2}, "b") {
+		t.Errorf("Expected MultiMap to contain b")
+	}
+}
+
+func TestMultiMap_GetAll_Missing(t *testing.T) {
+	mm := multimap.NewListMultiMap[int, int](2, 0.75)
+
+	if values := mm.GetAll(1); values != nil {
+		t.Errorf("Expected nil, got %v", values)
+	}
+}
+
+func TestMultiMap_GetAll_Copy(t *testing.T) {
+	mm := multimap.NewListMultiMap[int, int](2, 0.75)
+	mm.Put(1, 10)
+
+	mm.GetAll(1)[0] = 20
+
+	if !mm.Contains(1, 10) {
+		t.Errorf("Expected GetAll to return a copy")
+	}
+}
+
+func TestMultiMap_Remove(t *testing.T) {
+	for name, mm := range map[string]*multimap.MultiMap[int, int]{
+		"list": multimap.NewListMultiMap[int, int](2, 0.75),
+		"set":  multimap.NewSetMultiMap[int, int](2, 0.75),
+	} {
+		t.Run(name, func(t *testing.T) {
+			mm.Put(1, 10, 20)
+			mm.Remove(1, 10)
+			mm.Remove(1, 30)
+
+			if mm.Contains(1, 10) || mm.Count(1) != 1 {
+				t.Errorf("Expected only 20 to be left, got %v", mm.GetAll(1))
+			}
+
+			mm.Remove(1, 20)
+
+			if mm.ContainsKey(1) {
+				t.Errorf("Expected key without values to be removed")
+			}
+		})
+	}
+}
+
+func TestMultiMap_RemoveAll(t *testing.T) {
+	mm := multimap.NewListMultiMap[int, int](2, 0.75)
+	mm.Put(1, 10, 20)
+	mm.Put(2, 30)
+
+	mm.RemoveAll(1)
+
+	if mm.ContainsKey(1) || mm.Len() != 1 {
+		t.Errorf("Expected key 1 to be removed")
+	}
+}
+
+func TestMultiMap_Iter(t *testing.T) {
+	mm := multimap.NewListMultiMap[int, int](2, 0.75)
+	mm.Put(1, 10, 10)
+	mm.Put(2, 20)
+
+	sum := 0
+	for e := range mm.Iter() {
+		sum += e.Key + e.Value
+	}
+
+	if sum != 1+10+1+10+2+20 {
+		t.Errorf("Expected sum of pairs to be 44, got %d", sum)
+	}
+}
+
+func TestMultiMap_Clear(t *testing.T) {
+	mm := multimap.NewSetMultiMap[int, int](2, 0.75)
+	mm.Put(1, 10)
+	mm.Clear()
+
+	if mm.Len() != 0 || mm.KeyLen() != 0 {
+		t.Errorf("Expected MultiMap to be empty after Clear")
+	}
+}
+
+func TestMultiMap_String(t *testing.T) {
+	mm := multimap.NewListMultiMap[int, int](2, 0.75)
+	mm.Put(1, 10, 20)
+
+	if mm.String() != "{1: [10 20]}" {
+		t.Errorf("Expected {1: [10 20]}, got %s", mm.String())
+	}
+}
@@ -0,0 +1,137 @@
+// Package observable wraps a Map to notify dependents, such as caches and secondary indexes,
+// of its changes.
+package observable
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"iter"
+	"slices"
+	"sync"
+)
+
+var _ hashmap.Interface[string, int] = (*Map[string, int])(nil)
+
+// hook is a registered callback, compared by identity to be unregistered.
+type hook[F any] struct {
+	f F
+}
+
+// Map represents a Map whose changes are observed by callbacks and subscriptions.
+//
+// Callbacks registered with OnSet, OnDelete and OnClear are called synchronously after each
+// change, in the order they were registered, and may read the Map but not modify it.
+// Subscriptions receive the changes as Events on a channel.
+//
+// Like hashmap.Map, the Map must not be modified concurrently, but subscriptions can be
+// opened and closed from any goroutine.
+type Map[K, V any] struct {
+	m *hashmap.Map[K, V]
+
+	onSet    []*hook[func(key K, old, new V, existed bool)]
+	onDelete []*hook[func(key K, old V)]
+	onClear  []*hook[func()]
+
+	// mu guards subscriptions
+	mu            sync.Mutex
+	subscriptions []*Subscription[K, V]
+}
+
+// NewMap returns a new observable Map with the given size and threshold.
+func NewMap[K, V any](size uint32, threshold float32) *Map[K, V] {
+	return Wrap(hashmap.NewMap[K, V](size, threshold))
+}
+
+// Wrap returns an observable Map over an existing Map.
+// The Map must only be modified through the wrapper afterwards, or its changes go unnoticed.
+func Wrap[K, V any](m *hashmap.Map[K, V]) *Map[K, V] {
+	return &Map[K, V]{m: m}
+}
+
+// Unwrap returns the underlying Map.
+func (om *Map[K, V]) Unwrap() *hashmap.Map[K, V] { return om.m }
+
+// OnSet registers a callback called after a key is set, with its old value and whether it
+// existed, and returns a function unregistering it.
+func (om *Map[K, V]) OnSet(f func(key K, old, new V, existed bool)) (unregister func()) {
+	h := &hook[func(K, V, V, bool)]{f: f}
+	om.onSet = append(om.onSet, h)
+	return func() { om.onSet = remove(om.onSet, h) }
+}
+
+// OnDelete registers a callback called after an existing key is deleted, with its old value,
+// and returns a function unregistering it.
+func (om *Map[K, V]) OnDelete(f func(key K, old V)) (unregister func()) {
+	h := &hook[func(K, V)]{f: f}
+	om.onDelete = append(om.onDelete, h)
+	return func() { om.onDelete = remove(om.onDelete, h) }
+}
+
+// OnClear registers a callback called after the Map is cleared,
+// and returns a function unregistering it.
+func (om *Map[K, V]) OnClear(f func()) (unregister func()) {
+	h := &hook[func()]{f: f}
+	om.onClear = append(om.onClear, h)
+	return func() { om.onClear = remove(om.onClear, h) }
+}
+
+// remove returns the hooks without a hook.
+func remove[F any](hooks []*hook[F], h *hook[F]) []*hook[F] {
+	// Copy, so that hooks being called are not shifted
+	return slices.DeleteFunc(slices.Clone(hooks), func(other *hook[F]) bool { return other == h })
+}
+
+// Get returns the value associated with the key.
+func (om *Map[K, V]) Get(key K) (value V, ok bool) {
+	return om.m.Get(key)
+}
+
+// Set associates the value with the key, replacing any previous value,
+// and notifies the observers.
+func (om *Map[K, V]) Set(key K, value V) {
+	old, existed := om.m.Get(key)
+	om.m.Set(key, value)
+
+	for _, h := range om.onSet {
+		h.f(key, old, value, existed)
+	}
+	om.publish(Event[K, V]{Kind: EventSet, Key: key, Old: old, New: value, Existed: existed})
+}
+
+// Delete removes the key and its value, and notifies the observers if the key existed.
+func (om *Map[K, V]) Delete(key K) {
+	old, existed := om.m.Get(key)
+	if !existed {
+		return
+	}
+	om.m.Delete(key)
+
+	for _, h := range om.onDelete {
+		h.f(key, old)
+	}
+	om.publish(Event[K, V]{Kind: EventDelete, Key: key, Old: old, Existed: true})
+}
+
+// Clear removes all items from the Map, and notifies the observers once.
+func (om *Map[K, V]) Clear() {
+	om.m.Clear()
+
+	for _, h := range om.onClear {
+		h.f()
+	}
+	om.publish(Event[K, V]{Kind: EventClear})
+}
+
+// Len returns the number of items.
+func (om *Map[K, V]) Len() int {
+	return om.m.Len()
+}
+
+// All returns an iterator over all items.
+func (om *Map[K, V]) All() iter.Seq2[K, V] {
+	return om.m.All()
+}
+
+// String returns a string representation of the Map.
+func (om *Map[K, V]) String() string {
+	return om.m.String()
+}
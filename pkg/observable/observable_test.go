@@ -0,0 +1,72 @@
+package observable_test
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap/hashmaptest"
+	"github.com/pietroagazzi/gohashlib/pkg/observable"
+	"testing"
+)
+
+type user struct {
+	ID    int
+	Email string
+}
+
+func TestMap(t *testing.T) {
+	hashmaptest.Run(t, func() hashmap.Interface[int, int] { return observable.NewMap[int, int](8, 0.75) }, hashmaptest.Ints, hashmaptest.Ints)
+}
+
+func TestMap_Hooks(t *testing.T) {
+	users := observable.NewMap[int, user](8, 0.75)
+
+	// A secondary index of the users by email, kept in sync by the hooks
+	byEmail := make(map[string]int)
+	users.OnSet(func(id int, old, new user, existed bool) {
+		if existed {
+			delete(byEmail, old.Email)
+		}
+		byEmail[new.Email] = id
+	})
+	users.OnDelete(func(id int, old user) { delete(byEmail, old.Email) })
+	users.OnClear(func() { clear(byEmail) })
+
+	users.Set(1, user{1, "ada@example.com"})
+	users.Set(2, user{2, "alan@example.com"})
+	users.Set(1, user{1, "ada@lovelace.dev"})
+	users.Delete(2)
+	users.Delete(3)
+
+	if len(byEmail) != 1 || byEmail["ada@lovelace.dev"] != 1 {
+		t.Errorf("Expected index {ada@lovelace.dev: 1}, got %v", byEmail)
+	}
+
+	users.Clear()
+
+	if len(byEmail) != 0 || users.Len() != 0 {
+		t.Errorf("Expected empty index after Clear, got %v", byEmail)
+	}
+}
+
+func TestMap_OnSet_Unregister(t *testing.T) {
+	m := observable.Wrap(hashmap.NewMap[string, int](2, 0.75))
+
+	var calls []string
+	unregister := m.OnSet(func(key string, old, new int, existed bool) { calls = append(calls, "first") })
+	m.OnSet(func(key string, old, new int, existed bool) {
+		calls = append(calls, "second")
+		if existed && old != 1 {
+			t.Errorf("Expected old value 1, got %d", old)
+		}
+	})
+
+	m.Set("a", 1)
+	unregister()
+	m.Set("a", 2)
+
+	if len(calls) != 3 || calls[0] != "first" || calls[1] != "second" || calls[2] != "second" {
+		t.Errorf("Expected [first second second], got %v", calls)
+	}
+	if v, _ := m.Unwrap().Get("a"); v != 2 {
+		t.Errorf("Expected the underlying Map to hold 2, got %d", v)
+	}
+}
@@ -0,0 +1,166 @@
+package observable
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// EventKind is the kind of change of an Event.
+type EventKind int
+
+const (
+	// EventSet is a key set, with its old value if it existed
+	EventSet EventKind = iota
+	// EventDelete is an existing key deleted, with its old value
+	EventDelete
+	// EventClear is the Map cleared, with no key
+	EventClear
+)
+
+// String returns the name of the kind.
+func (k EventKind) String() string {
+	switch k {
+	case EventSet:
+		return "set"
+	case EventDelete:
+		return "delete"
+	case EventClear:
+		return "clear"
+	}
+	return "unknown"
+}
+
+// Event is a change of a Map.
+type Event[K, V any] struct {
+	Kind    EventKind
+	Key     K
+	Old     V
+	New     V
+	Existed bool
+}
+
+// Policy decides what happens to an event when the buffer of a subscription is full.
+type Policy int
+
+const (
+	// Block waits for the subscriber to receive the event, blocking the change of the Map.
+	// The subscriber must not modify the Map, or it deadlocks.
+	Block Policy = iota
+	// DropNewest discards the event.
+	DropNewest
+	// DropOldest discards the oldest buffered event to make room for the event.
+	DropOldest
+	// Disconnect closes the subscription, so that a subscriber that falls behind
+	// notices it and can rebuild its state from the Map.
+	Disconnect
+)
+
+// Subscription represents a stream of the events of a Map.
+type Subscription[K, V any] struct {
+	// C receives the events, and is closed when the subscription is closed
+	C <-chan Event[K, V]
+
+	ch     chan Event[K, V]
+	policy Policy
+	m      *Map[K, V]
+	// done is closed when the subscription is closed, to unblock a blocked send
+	done chan struct{}
+	// sendMu is held while sending, so that the channel is not closed during a send
+	sendMu    sync.Mutex
+	closeOnce sync.Once
+	dropped   atomic.Uint64
+}
+
+// Subscribe opens a subscription receiving the events of the Map on a channel with the
+// given buffer, applying the policy when the buffer is full.
+func (om *Map[K, V]) Subscribe(buffer int, policy Policy) *Subscription[K, V] {
+	ch := make(chan Event[K, V], max(buffer, 0))
+	s := &Subscription[K, V]{C: ch, ch: ch, policy: policy, m: om, done: make(chan struct{})}
+
+	om.mu.Lock()
+	om.subscriptions = append(om.subscriptions, s)
+	om.mu.Unlock()
+
+	return s
+}
+
+// Dropped returns the number of events discarded by the policy.
+func (s *Subscription[K, V]) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close closes the subscription and its channel. It can be called more than once.
+func (s *Subscription[K, V]) Close() {
+	s.close(false)
+}
+
+// close closes the subscription, with sendMu already held if locked.
+func (s *Subscription[K, V]) close(locked bool) {
+	s.closeOnce.Do(func() {
+		// Unblock a blocked send first, so that sendMu is released
+		close(s.done)
+
+		if !locked {
+			s.sendMu.Lock()
+			defer s.sendMu.Unlock()
+		}
+		close(s.ch)
+
+		s.m.mu.Lock()
+		defer s.m.mu.Unlock()
+
+		s.m.subscriptions = slices.DeleteFunc(s.m.subscriptions, func(other *Subscription[K, V]) bool { return other == s })
+	})
+}
+
+// publish sends an event to the subscriptions.
+func (om *Map[K, V]) publish(e Event[K, V]) {
+	om.mu.Lock()
+	subscriptions := slices.Clone(om.subscriptions)
+	om.mu.Unlock()
+
+	for _, s := range subscriptions {
+		s.send(e)
+	}
+}
+
+// send sends an event to the subscription, applying its policy if the buffer is full.
+func (s *Subscription[K, V]) send(e Event[K, V]) {
+	// Hold the lock, so that Close does not close the channel during the send
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	select {
+	case <-s.done:
+		return
+	case s.ch <- e:
+		return
+	default:
+	}
+
+	switch s.policy {
+	case Block:
+		select {
+		case s.ch <- e:
+		case <-s.done:
+		}
+	case DropNewest:
+		s.dropped.Add(1)
+	case DropOldest:
+		// The subscriber may have made room meanwhile
+		select {
+		case <-s.ch:
+			s.dropped.Add(1)
+		default:
+		}
+		select {
+		case s.ch <- e:
+		default:
+			s.dropped.Add(1)
+		}
+	case Disconnect:
+		s.dropped.Add(1)
+		s.close(true)
+	}
+}
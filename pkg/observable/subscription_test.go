@@ -0,0 +1,134 @@
+package observable_test
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/observable"
+	"testing"
+	"time"
+)
+
+// drain returns the events buffered in a subscription.
+func drain(s *observable.Subscription[string, int]) []observable.Event[string, int] {
+	var events []observable.Event[string, int]
+	for {
+		select {
+		case e, ok := <-s.C:
+			if !ok {
+				return events
+			}
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
+func TestSubscription_Events(t *testing.T) {
+	m := observable.NewMap[string, int](2, 0.75)
+	s := m.Subscribe(10, observable.Block)
+
+	m.Set("a", 1)
+	m.Set("a", 2)
+	m.Delete("a")
+	m.Clear()
+
+	events := drain(s)
+	if len(events) != 4 {
+		t.Fatalf("Expected 4 events, got %v", events)
+	}
+
+	if e := events[1]; e.Kind != observable.EventSet || !e.Existed || e.Old != 1 || e.New != 2 {
+		t.Errorf("Expected a to be set from 1 to 2, got %+v", e)
+	}
+	if e := events[2]; e.Kind != observable.EventDelete || e.Old != 2 {
+		t.Errorf("Expected a to be deleted, got %+v", e)
+	}
+	if events[3].Kind != observable.EventClear || events[3].Kind.String() != "clear" {
+		t.Errorf("Expected the Map to be cleared, got %+v", events[3])
+	}
+
+	s.Close()
+	s.Close()
+	m.Set("b", 1)
+
+	if _, ok := <-s.C; ok {
+		t.Errorf("Expected the channel to be closed")
+	}
+}
+
+func TestSubscription_DropNewest(t *testing.T) {
+	m := observable.NewMap[string, int](2, 0.75)
+	s := m.Subscribe(2, observable.DropNewest)
+
+	for i := 0; i < 5; i++ {
+		m.Set("a", i)
+	}
+
+	events := drain(s)
+	if len(events) != 2 || events[0].New != 0 || events[1].New != 1 || s.Dropped() != 3 {
+		t.Errorf("Expected the first 2 events and 3 dropped, got %v and %d", events, s.Dropped())
+	}
+}
+
+func TestSubscription_DropOldest(t *testing.T) {
+	m := observable.NewMap[string, int](2, 0.75)
+	s := m.Subscribe(2, observable.DropOldest)
+
+	for i := 0; i < 5; i++ {
+		m.Set("a", i)
+	}
+
+	events := drain(s)
+	if len(events) != 2 || events[0].New != 3 || events[1].New != 4 || s.Dropped() != 3 {
+		t.Errorf("Expected the last 2 events and 3 dropped, got %v and %d", events, s.Dropped())
+	}
+}
+
+func TestSubscription_Disconnect(t *testing.T) {
+	m := observable.NewMap[string, int](2, 0.75)
+	s := m.Subscribe(1, observable.Disconnect)
+
+	m.Set("a", 1)
+	m.Set("b", 2)
+	m.Set("c", 3)
+
+	// The buffered event is delivered before the channel is closed
+	events := drain(s)
+	if len(events) != 1 || events[0].Key != "a" || s.Dropped() != 1 {
+		t.Errorf("Expected the first event and 1 dropped, got %v and %d", events, s.Dropped())
+	}
+	if _, ok := <-s.C; ok {
+		t.Errorf("Expected the channel to be closed")
+	}
+}
+
+func TestSubscription_Block(t *testing.T) {
+	m := observable.NewMap[string, int](2, 0.75)
+	s := m.Subscribe(0, observable.Block)
+
+	received := make(chan int)
+	go func() {
+		sum := 0
+		for e := range s.C {
+			sum += e.New
+		}
+		received <- sum
+	}()
+
+	for i := 1; i <= 100; i++ {
+		m.Set("a", i)
+	}
+	s.Close()
+
+	if sum := <-received; sum != 5050 {
+		t.Errorf("Expected every event to be received, got sum %d", sum)
+	}
+
+	// Closing unblocks a send waiting for a subscriber that stopped receiving
+	stalled := m.Subscribe(0, observable.Block)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		stalled.Close()
+	}()
+
+	m.Set("b", 1)
+}
@@ -0,0 +1,34 @@
+package ring
+
+// Jump returns the bucket in [0, buckets) of a key, with the jump consistent hash of
+// Lamping and Veach. When the number of buckets grows from n to n+1, only 1/(n+1) of the
+// keys move, all to the new bucket.
+// https://arxiv.org/abs/1406.2294
+//
+// Buckets are numbers rather than nodes, so only the last bucket can be removed.
+// It returns an error if the key cannot be encoded.
+func Jump[K any](key K, buckets int) (int, error) {
+	if buckets <= 0 {
+		return 0, ErrEmpty
+	}
+
+	h, err := hashKey(key)
+	if err != nil {
+		return 0, err
+	}
+
+	return JumpHash(h, buckets), nil
+}
+
+// JumpHash returns the bucket in [0, buckets) of a 64-bit hash, with the jump consistent hash.
+func JumpHash(h uint64, buckets int) int {
+	b, j := int64(-1), int64(0)
+
+	for j < int64(buckets) {
+		b = j
+		h = h*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((h>>33)+1)))
+	}
+
+	return int(b)
+}
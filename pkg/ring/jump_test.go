@@ -0,0 +1,42 @@
+package ring_test
+
+import (
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/ring"
+	"testing"
+)
+
+func TestJump(t *testing.T) {
+	if _, err := ring.Jump(job{}, 0); !errors.Is(err, ring.ErrEmpty) {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+
+	counts := make([]int, 10)
+	for i := 0; i < 10000; i++ {
+		before, _ := ring.Jump(job{"acme", i}, 10)
+		after, _ := ring.Jump(job{"acme", i}, 11)
+
+		// Growing moves keys to the new bucket only
+		if after != before && after != 10 {
+			t.Errorf("Expected key to stay in %d or move to 10, got %d", before, after)
+		}
+		counts[before]++
+	}
+
+	for b, n := range counts {
+		if n < 700 || n > 1300 {
+			t.Errorf("Expected about 1000 keys in bucket %d, got %d", b, n)
+		}
+	}
+}
+
+func TestJumpHash(t *testing.T) {
+	for h := uint64(0); h < 1000; h++ {
+		if b := ring.JumpHash(h, 1); b != 0 {
+			t.Errorf("Expected bucket 0 with a single bucket, got %d", b)
+		}
+		if b := ring.JumpHash(h, 1000); b < 0 || b >= 1000 {
+			t.Errorf("Expected bucket in [0, 1000), got %d", b)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package ring
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/utils"
+	"math"
+	"slices"
+)
+
+// Rendezvous assigns keys to nodes with rendezvous, or highest random weight, hashing.
+// https://en.wikipedia.org/wiki/Rendezvous_hashing
+//
+// Every node gets a score for a key, and the key is assigned to the node with the highest
+// score. Removing a node only moves its keys, and adding one only moves the keys it wins.
+// Lookups take time proportional to the number of nodes, but need no virtual nodes.
+type Rendezvous[K, N any] struct {
+	members []member[N]
+}
+
+// NewRendezvous returns a new Rendezvous with no nodes.
+func NewRendezvous[K, N any]() *Rendezvous[K, N] {
+	return &Rendezvous[K, N]{}
+}
+
+// Len returns the number of nodes.
+func (r *Rendezvous[K, N]) Len() int { return len(r.members) }
+
+// find returns the position of a node in members, or -1.
+func (r *Rendezvous[K, N]) find(node N) int {
+	return slices.IndexFunc(r.members, func(m member[N]) bool { return utils.Equaler(m.node, node) })
+}
+
+// AddNode adds a node with a weight, or changes the weight of a node already added.
+// A node of weight w gets about w times the keys.
+// It returns an error if the node cannot be encoded or the weight is not positive.
+func (r *Rendezvous[K, N]) AddNode(node N, weight int) error {
+	if weight <= 0 {
+		return ErrWeight
+	}
+
+	h, err := hashKey(node)
+	if err != nil {
+		return err
+	}
+
+	if i := r.find(node); i >= 0 {
+		r.members[i].weight = weight
+		return nil
+	}
+
+	r.members = append(r.members, member[N]{node: node, hash: h, weight: weight})
+	return nil
+}
+
+// RemoveNode removes a node, and reports whether it was added.
+func (r *Rendezvous[K, N]) RemoveNode(node N) bool {
+	i := r.find(node)
+	if i < 0 {
+		return false
+	}
+
+	r.members = slices.Delete(r.members, i, i+1)
+	return true
+}
+
+// score returns the score of a node for the hash of a key.
+//
+// The weighted score -weight / ln(u), for u uniform in (0, 1), is highest for each node
+// with a probability proportional to its weight.
+func score[N any](key uint64, m member[N]) float64 {
+	u := (float64(utils.Mix64(key^m.hash)>>11) + 0.5) / (1 << 53)
+	return -float64(m.weight) / math.Log(u)
+}
+
+// Get returns the node a key is assigned to, the node with the highest score.
+// It returns an error if the key cannot be encoded or there are no nodes.
+func (r *Rendezvous[K, N]) Get(key K) (node N, err error) {
+	if len(r.members) == 0 {
+		return node, ErrEmpty
+	}
+
+	h, err := hashKey(key)
+	if err != nil {
+		return node, err
+	}
+
+	best := math.Inf(-1)
+	for _, m := range r.members {
+		if s := score(h, m); s > best {
+			node, best = m.node, s
+		}
+	}
+
+	return node, nil
+}
+
+// GetN returns up to n distinct nodes for a key, e.g. to hold its replicas,
+// from the highest to the lowest score.
+// It returns an error if the key cannot be encoded or there are no nodes.
+func (r *Rendezvous[K, N]) GetN(key K, n int) ([]N, error) {
+	if len(r.members) == 0 {
+		return nil, ErrEmpty
+	}
+
+	h, err := hashKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make([]float64, len(r.members))
+	order := make([]int, len(r.members))
+	for i, m := range r.members {
+		scores[i], order[i] = score(h, m), i
+	}
+
+	slices.SortFunc(order, func(a, b int) int {
+		switch {
+		case scores[a] > scores[b]:
+			return -1
+		case scores[a] < scores[b]:
+			return 1
+		}
+		return 0
+	})
+
+	result := make([]N, max(min(n, len(order)), 0))
+	for i := range result {
+		result[i] = r.members[order[i]].node
+	}
+
+	return result, nil
+}
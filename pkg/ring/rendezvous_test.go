@@ -0,0 +1,66 @@
+package ring_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/pietroagazzi/gohashlib/pkg/ring"
+	"testing"
+)
+
+func TestRendezvous_Get(t *testing.T) {
+	r := ring.NewRendezvous[job, string]()
+
+	if _, err := r.Get(job{}); !errors.Is(err, ring.ErrEmpty) {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		_ = r.AddNode(fmt.Sprintf("worker-%d", i), 1)
+	}
+	_ = r.AddNode("worker-4", 4)
+
+	before := assign(t, r.Get, 10000)
+
+	counts := make(map[string]int)
+	for _, node := range before {
+		counts[node]++
+	}
+	if counts["worker-4"] < 4000 || counts["worker-4"] > 6000 {
+		t.Errorf("Expected about 5000 jobs on worker-4, got %d", counts["worker-4"])
+	}
+
+	// Only the jobs of the removed node move
+	r.RemoveNode("worker-4")
+	after := assign(t, r.Get, 10000)
+
+	for j, node := range before {
+		if node != "worker-4" && after[j] != node {
+			t.Errorf("Expected %v to stay on %s, got %s", j, node, after[j])
+		}
+	}
+
+	if r.Len() != 4 || r.RemoveNode("worker-4") {
+		t.Errorf("Expected worker-4 to be removed")
+	}
+}
+
+func TestRendezvous_GetN(t *testing.T) {
+	r := ring.NewRendezvous[job, string]()
+	_ = r.AddNode("a", 1)
+	_ = r.AddNode("b", 1)
+	_ = r.AddNode("c", 1)
+
+	nodes, err := r.GetN(job{"acme", 1}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, _ := r.Get(job{"acme", 1})
+	if len(nodes) != 2 || nodes[0] != first || nodes[0] == nodes[1] {
+		t.Errorf("Expected 2 distinct nodes led by %s, got %v", first, nodes)
+	}
+
+	if err := r.AddNode("d", -1); !errors.Is(err, ring.ErrWeight) {
+		t.Errorf("Expected ErrWeight, got %v", err)
+	}
+}
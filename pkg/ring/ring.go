@@ -0,0 +1,188 @@
+// Package ring assigns keys to nodes so that few keys move when nodes are added or removed.
+package ring
+
+import (
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/utils"
+	"slices"
+)
+
+// ErrEmpty is returned when looking up a key with no nodes to assign it to.
+var ErrEmpty = errors.New("ring: no nodes")
+
+// ErrWeight is returned when adding a node with a weight that is not positive.
+var ErrWeight = errors.New("ring: weight must be positive")
+
+// hashKey returns the hash keys and nodes are placed by.
+//
+// Uses the encoding and FNV-1a hash of hashmap.Map keys, mixed because FNV-1a spreads
+// short inputs poorly over the high bits, which decide the order of the hashes.
+func hashKey(value any) (uint64, error) {
+	h, err := utils.Hash64(value)
+	return utils.Mix64(h), err
+}
+
+// point is a virtual node, a position of a node on the ring.
+type point[N any] struct {
+	hash uint64
+	node N
+}
+
+// member is a node of the ring, with its weight.
+type member[N any] struct {
+	node   N
+	hash   uint64
+	weight int
+}
+
+// Ring represents a consistent hash ring.
+// https://en.wikipedia.org/wiki/Consistent_hashing
+//
+// Each node is placed on a circle of 64-bit hashes at several points, its virtual nodes, and
+// a key is assigned to the node of the first point following its hash. Adding or removing a
+// node only moves the keys of its points, instead of reshuffling all the keys as Map.Index
+// modulo the number of nodes does. A node of weight w has w times the virtual nodes, and gets
+// about w times the keys.
+type Ring[K, N any] struct {
+	// virtualNodes is the number of points of a node of weight 1
+	virtualNodes int
+	// points are the virtual nodes of all the nodes, sorted by hash
+	points  []point[N]
+	members []member[N]
+}
+
+// NewRing returns a new Ring placing virtualNodes points for each unit of weight of a node.
+// More points spread the keys more evenly, at the cost of memory.
+func NewRing[K, N any](virtualNodes int) *Ring[K, N] {
+	return &Ring[K, N]{virtualNodes: max(virtualNodes, 1)}
+}
+
+// Len returns the number of nodes.
+func (r *Ring[K, N]) Len() int { return len(r.members) }
+
+// Nodes returns the nodes, in the order they were added.
+func (r *Ring[K, N]) Nodes() []N {
+	result := make([]N, len(r.members))
+	for i, m := range r.members {
+		result[i] = m.node
+	}
+	return result
+}
+
+// Weight returns the weight of a node, and whether it is in the ring.
+func (r *Ring[K, N]) Weight(node N) (int, bool) {
+	i := r.find(node)
+	if i < 0 {
+		return 0, false
+	}
+	return r.members[i].weight, true
+}
+
+// find returns the position of a node in members, or -1.
+func (r *Ring[K, N]) find(node N) int {
+	return slices.IndexFunc(r.members, func(m member[N]) bool { return utils.Equaler(m.node, node) })
+}
+
+// AddNode adds a node with a weight, or changes the weight of a node already in the ring.
+// It returns an error if the node cannot be encoded or the weight is not positive.
+func (r *Ring[K, N]) AddNode(node N, weight int) error {
+	if weight <= 0 {
+		return ErrWeight
+	}
+
+	h, err := hashKey(node)
+	if err != nil {
+		return err
+	}
+
+	if i := r.find(node); i >= 0 {
+		r.members[i].weight = weight
+	} else {
+		r.members = append(r.members, member[N]{node: node, hash: h, weight: weight})
+	}
+
+	r.build()
+	return nil
+}
+
+// RemoveNode removes a node, and reports whether it was in the ring.
+func (r *Ring[K, N]) RemoveNode(node N) bool {
+	i := r.find(node)
+	if i < 0 {
+		return false
+	}
+
+	r.members = slices.Delete(r.members, i, i+1)
+	r.build()
+
+	return true
+}
+
+// build places the virtual nodes of all the nodes.
+func (r *Ring[K, N]) build() {
+	r.points = r.points[:0]
+
+	for _, m := range r.members {
+		h := m.hash
+		for i := 0; i < m.weight*r.virtualNodes; i++ {
+			// Consecutive values of the SplitMix64 sequence seeded by the node
+			h += 0x9e3779b97f4a7c15
+			r.points = append(r.points, point[N]{hash: utils.Mix64(h), node: m.node})
+		}
+	}
+
+	slices.SortStableFunc(r.points, func(a, b point[N]) int {
+		switch {
+		case a.hash < b.hash:
+			return -1
+		case a.hash > b.hash:
+			return 1
+		}
+		return 0
+	})
+}
+
+// Get returns the node a key is assigned to.
+// It returns an error if the key cannot be encoded or the ring has no nodes.
+func (r *Ring[K, N]) Get(key K) (node N, err error) {
+	nodes, err := r.GetN(key, 1)
+	if err != nil {
+		return node, err
+	}
+	return nodes[0], nil
+}
+
+// GetN returns up to n distinct nodes for a key, e.g. to hold its replicas: the node it is
+// assigned to, followed by the nodes of the next points of the ring.
+// It returns an error if the key cannot be encoded or the ring has no nodes.
+func (r *Ring[K, N]) GetN(key K, n int) ([]N, error) {
+	if len(r.members) == 0 {
+		return nil, ErrEmpty
+	}
+
+	h, err := hashKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	n = max(min(n, len(r.members)), 0)
+	result := make([]N, 0, n)
+
+	// The first point following the hash, wrapping around the ring
+	start, _ := slices.BinarySearchFunc(r.points, h, func(p point[N], h uint64) int {
+		if p.hash < h {
+			return -1
+		}
+		return 1
+	})
+
+	for i := 0; i < len(r.points) && len(result) < n; i++ {
+		node := r.points[(start+i)%len(r.points)].node
+
+		if !slices.ContainsFunc(result, func(other N) bool { return utils.Equaler(other, node) }) {
+			result = append(result, node)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,141 @@
+package ring_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/pietroagazzi/gohashlib/pkg/ring"
+	"testing"
+)
+
+type job struct {
+	Tenant string
+	ID     int
+}
+
+// assign returns the node of each of n jobs.
+func assign(t *testing.T, get func(job) (string, error), n int) map[job]string {
+	t.Helper()
+
+	result := make(map[job]string, n)
+	for i := 0; i < n; i++ {
+		j := job{"acme", i}
+
+		node, err := get(j)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result[j] = node
+	}
+
+	return result
+}
+
+// moved returns the number of jobs assigned to a different node.
+func moved(before, after map[job]string) int {
+	n := 0
+	for j, node := range before {
+		if after[j] != node {
+			n++
+		}
+	}
+	return n
+}
+
+func TestRing_Get(t *testing.T) {
+	r := ring.NewRing[job, string](100)
+
+	if _, err := r.Get(job{}); !errors.Is(err, ring.ErrEmpty) {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		_ = r.AddNode(fmt.Sprintf("worker-%d", i), 1)
+	}
+
+	before := assign(t, r.Get, 10000)
+
+	counts := make(map[string]int)
+	for _, node := range before {
+		counts[node]++
+	}
+	for node, n := range counts {
+		if n < 1500 || n > 3500 {
+			t.Errorf("Expected about 2500 jobs on %s, got %d", node, n)
+		}
+	}
+
+	// Only the jobs of the new node move
+	_ = r.AddNode("worker-4", 1)
+	after := assign(t, r.Get, 10000)
+
+	for j, node := range before {
+		if after[j] != node && after[j] != "worker-4" {
+			t.Errorf("Expected %v to stay on %s or move to worker-4, got %s", j, node, after[j])
+		}
+	}
+	if n := moved(before, after); n > 3000 {
+		t.Errorf("Expected about 2000 jobs to move, got %d", n)
+	}
+
+	// Removing the node moves them back
+	if !r.RemoveNode("worker-4") {
+		t.Errorf("Expected worker-4 to be removed")
+	}
+	if n := moved(before, assign(t, r.Get, 10000)); n != 0 {
+		t.Errorf("Expected no job to move, got %d", n)
+	}
+	if r.RemoveNode("worker-4") {
+		t.Errorf("Expected worker-4 not to be in the ring")
+	}
+}
+
+func TestRing_AddNode_Weight(t *testing.T) {
+	r := ring.NewRing[job, string](100)
+
+	_ = r.AddNode("small", 1)
+	_ = r.AddNode("large", 3)
+
+	counts := make(map[string]int)
+	for _, node := range assign(t, r.Get, 10000) {
+		counts[node]++
+	}
+	if counts["large"] < 6500 || counts["large"] > 8500 {
+		t.Errorf("Expected about 7500 jobs on large, got %d", counts["large"])
+	}
+
+	// Changing the weight does not add the node twice
+	_ = r.AddNode("large", 1)
+	if w, _ := r.Weight("large"); w != 1 || r.Len() != 2 {
+		t.Errorf("Expected 2 nodes and weight 1, got %d and %d", r.Len(), w)
+	}
+
+	if err := r.AddNode("none", 0); !errors.Is(err, ring.ErrWeight) {
+		t.Errorf("Expected ErrWeight, got %v", err)
+	}
+}
+
+func TestRing_GetN(t *testing.T) {
+	r := ring.NewRing[job, string](10)
+	_ = r.AddNode("a", 1)
+	_ = r.AddNode("b", 1)
+	_ = r.AddNode("c", 1)
+
+	for i := 0; i < 100; i++ {
+		nodes, err := r.GetN(job{"acme", i}, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Replicas are on distinct nodes, led by the node of the key
+		if len(nodes) != 3 || nodes[0] == nodes[1] || nodes[1] == nodes[2] || nodes[0] == nodes[2] {
+			t.Fatalf("Expected 3 distinct nodes, got %v", nodes)
+		}
+		if node, _ := r.Get(job{"acme", i}); node != nodes[0] {
+			t.Errorf("Expected %s first, got %v", node, nodes)
+		}
+	}
+
+	if nodes := r.Nodes(); len(nodes) != 3 || nodes[0] != "a" {
+		t.Errorf("Expected [a b c], got %v", nodes)
+	}
+}
@@ -0,0 +1,260 @@
+package set
+
+import (
+	"fmt"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"iter"
+	"math/bits"
+)
+
+// Integer is the constraint of the values of a BitSet.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// BitSet represents a set of small non-negative integers.
+//
+// Each value is a bit in a slice of words, so membership needs neither hashing nor
+// allocation, and set operations work on 64 values at a time.
+// The memory used grows with the largest value, so BitSet suits dense domains
+// such as enum IDs or node indices.
+type BitSet[T Integer] struct {
+	words []uint64
+}
+
+// NewBitSet creates a new BitSet with room for the values lower than capacity.
+// The BitSet grows as needed when larger values are added.
+func NewBitSet[T Integer](capacity int) *BitSet[T] {
+	return &BitSet[T]{words: make([]uint64, (max(capacity, 0)+63)/64)}
+}
+
+// position returns the word and the bit of a value.
+func position[T Integer](value T) (word int, bit uint) {
+	return int(uint64(value) / 64), uint(uint64(value) % 64)
+}
+
+// Add adds values to the BitSet.
+// It panics if a value is negative.
+func (b *BitSet[T]) Add(values ...T) {
+	for _, v := range values {
+		if v < 0 {
+			panic(fmt.Sprintf("set: negative value %v added to a BitSet", v))
+		}
+
+		word, bit := position(v)
+		if word >= len(b.words) {
+			b.words = append(b.words, make([]uint64, word+1-len(b.words))...)
+		}
+		b.words[word] |= 1 << bit
+	}
+}
+
+// Remove removes a value from the BitSet.
+func (b *BitSet[T]) Remove(value T) {
+	if !b.Contains(value) {
+		return
+	}
+
+	word, bit := position(value)
+	b.words[word] &^= 1 << bit
+}
+
+// Contains checks if the BitSet contains a value.
+func (b *BitSet[T]) Contains(value T) bool {
+	if value < 0 {
+		return false
+	}
+
+	word, bit := position(value)
+	return word < len(b.words) && b.words[word]&(1<<bit) != 0
+}
+
+// Size returns the number of values the BitSet can hold without growing.
+func (b *BitSet[T]) Size() uint32 {
+	return uint32(len(b.words) * 64)
+}
+
+// Len returns the number of values in the BitSet.
+func (b *BitSet[T]) Len() int {
+	count := 0
+	for _, w := range b.words {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+// Clear removes all values from the BitSet.
+func (b *BitSet[T]) Clear() {
+	clear(b.words)
+}
+
+// Copy returns a copy of the BitSet.
+func (b *BitSet[T]) Copy() *BitSet[T] {
+	return &BitSet[T]{words: append([]uint64(nil), b.words...)}
+}
+
+// Union returns a new BitSet with all the values that are in either BitSet.
+func (b *BitSet[T]) Union(other *BitSet[T]) *BitSet[T] {
+	result := &BitSet[T]{words: make([]uint64, max(len(b.words), len(other.words)))}
+	for i := range result.words {
+		result.words[i] = b.word(i) | other.word(i)
+	}
+	return result
+}
+
+// Intersection returns a new BitSet with all the values that are in both BitSets.
+func (b *BitSet[T]) Intersection(other *BitSet[T]) *BitSet[T] {
+	result := &BitSet[T]{words: make([]uint64, min(len(b.words), len(other.words)))}
+	for i := range result.words {
+		result.words[i] = b.words[i] & other.words[i]
+	}
+	return result
+}
+
+// Difference returns a new BitSet with all the values that are in the first BitSet but not in the second.
+func (b *BitSet[T]) Difference(other *BitSet[T]) *BitSet[T] {
+	result := &BitSet[T]{words: make([]uint64, len(b.words))}
+	for i := range result.words {
+		result.words[i] = b.words[i] &^ other.word(i)
+	}
+	return result
+}
+
+// SymmetricDifference returns a new BitSet with all the values that are in exactly one of the BitSets.
+func (b *BitSet[T]) SymmetricDifference(other *BitSet[T]) *BitSet[T] {
+	result := &BitSet[T]{words: make([]uint64, max(len(b.words), len(other.words)))}
+	for i := range result.words {
+		result.words[i] = b.word(i) ^ other.word(i)
+	}
+	return result
+}
+
+// Equal checks if two BitSets hold the same values.
+func (b *BitSet[T]) Equal(other *BitSet[T]) bool {
+	for i := range max(len(b.words), len(other.words)) {
+		if b.word(i) != other.word(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// Subset checks if the BitSet is a subset of another BitSet.
+func (b *BitSet[T]) Subset(other *BitSet[T]) bool {
+	for i, w := range b.words {
+		if w&^other.word(i) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Superset checks if the BitSet is a superset of another BitSet.
+func (b *BitSet[T]) Superset(other *BitSet[T]) bool {
+	return other.Subset(b)
+}
+
+// IsDisjoint checks if the BitSet has no values in common with another BitSet.
+func (b *BitSet[T]) IsDisjoint(other *BitSet[T]) bool {
+	return b.Intersection(other).Len() == 0
+}
+
+// Any checks if any value in the BitSet satisfies the callback.
+func (b *BitSet[T]) Any(callback func(T) bool) bool {
+	for value := range b.Elements() {
+		if callback(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// All checks if all values in the BitSet satisfy the callback.
+func (b *BitSet[T]) All(callback func(T) bool) bool {
+	for value := range b.Elements() {
+		if !callback(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Elements returns an iterator over the values in the BitSet, in increasing order.
+func (b *BitSet[T]) Elements() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i, w := range b.words {
+			for w != 0 {
+				bit := bits.TrailingZeros64(w)
+				if !yield(T(i*64 + bit)) {
+					return
+				}
+				// Clear the lowest set bit
+				w &= w - 1
+			}
+		}
+	}
+}
+
+// Iter returns a channel that yields each value in the BitSet, in increasing order.
+func (b *BitSet[T]) Iter() <-chan T {
+	ch := make(chan T)
+	go func() {
+		for value := range b.Elements() {
+			ch <- value
+		}
+		close(ch)
+	}()
+	return ch
+}
+
+// ToSlice returns a slice containing all values in the BitSet, in increasing order.
+func (b *BitSet[T]) ToSlice() []T {
+	slice := make([]T, 0, b.Len())
+	for value := range b.Elements() {
+		slice = append(slice, value)
+	}
+	return slice
+}
+
+// ToSet returns a Set containing all values in the BitSet.
+func (b *BitSet[T]) ToSet() *Set[T] {
+	s := NewSet[T](uint32(b.Len()), hashmap.DefaultThreshold)
+	for value := range b.Elements() {
+		s.Add(value)
+	}
+	return s
+}
+
+// BitSetFromSet returns a BitSet containing all values in the set.
+// It panics if a value is negative.
+func BitSetFromSet[T Integer](s *Set[T]) *BitSet[T] {
+	b := NewBitSet[T](0)
+	for value := range s.Elements() {
+		b.Add(value)
+	}
+	return b
+}
+
+// String returns a string representation of the BitSet.
+func (b *BitSet[T]) String() string {
+	out := "{"
+
+	for value := range b.Elements() {
+		out += fmt.Sprintf("%v, ", value)
+	}
+
+	if len(out) > 1 {
+		out = out[:len(out)-2]
+	}
+
+	return out + "}"
+}
+
+// word returns the i-th word, or zero if the BitSet is shorter.
+func (b *BitSet[T]) word(i int) uint64 {
+	if i < len(b.words) {
+		return b.words[i]
+	}
+	return 0
+}
@@ -0,0 +1,137 @@
+package set_test
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"testing"
+)
+
+func TestBitSet_Add(t *testing.T) {
+	b := set.NewBitSet[int](10)
+	b.Add(1, 3, 200, 3)
+
+	if !b.Contains(1) || !b.Contains(200) || b.Contains(2) || b.Contains(-1) || b.Contains(1000) {
+		t.Errorf("Unexpected values in %v", b)
+	}
+	if b.Len() != 3 {
+		t.Errorf("Expected length to be 3, got %d", b.Len())
+	}
+	if b.Size() < 201 {
+		t.Errorf("Expected BitSet to grow, got size %d", b.Size())
+	}
+}
+
+func TestBitSet_Add_Negative(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected Add to panic on a negative value")
+		}
+	}()
+
+	set.NewBitSet[int](0).Add(-1)
+}
+
+func TestBitSet_Remove(t *testing.T) {
+	b := set.NewBitSet[uint8](0)
+	b.Add(1, 2)
+	b.Remove(1)
+	b.Remove(100)
+
+	if b.Contains(1) || b.Len() != 1 {
+		t.Errorf("Expected only 2 to be left, got %v", b)
+	}
+
+	b.Clear()
+
+	if b.Len() != 0 {
+		t.Errorf("Expected BitSet to be empty after Clear")
+	}
+}
+
+func TestBitSet_Operations(t *testing.T) {
+	b1 := set.NewBitSet[int](0)
+	b1.Add(1, 2, 3, 100)
+
+	b2 := set.NewBitSet[int](0)
+	b2.Add(3, 4)
+
+	if union := b1.Union(b2); union.String() != "{1, 2, 3, 4, 100}" {
+		t.Errorf("Unexpected union %v", union)
+	}
+	if intersection := b1.Intersection(b2); intersection.String() != "{3}" {
+		t.Errorf("Unexpected intersection %v", intersection)
+	}
+	if difference := b1.Difference(b2); difference.String() != "{1, 2, 100}" {
+		t.Errorf("Unexpected difference %v", difference)
+	}
+	if difference := b1.SymmetricDifference(b2); difference.String() != "{1, 2, 4, 100}" {
+		t.Errorf("Unexpected symmetric difference %v", difference)
+	}
+}
+
+func TestBitSet_Comparisons(t *testing.T) {
+	b1 := set.NewBitSet[int](1000)
+	b1.Add(1, 2)
+
+	b2 := set.NewBitSet[int](0)
+	b2.Add(2, 1, 70)
+
+	if !b1.Subset(b2) || b2.Subset(b1) || !b2.Superset(b1) {
+		t.Errorf("Expected %v to be a subset of %v", b1, b2)
+	}
+
+	b2.Remove(70)
+
+	if !b1.Equal(b2) || !b2.Equal(b1) {
+		t.Errorf("Expected BitSets of different sizes to be equal")
+	}
+
+	b3 := set.NewBitSet[int](0)
+	b3.Add(3)
+
+	if !b1.IsDisjoint(b3) || b1.IsDisjoint(b2) {
+		t.Errorf("Unexpected IsDisjoint result")
+	}
+}
+
+func TestBitSet_Elements(t *testing.T) {
+	b := set.NewBitSet[int](0)
+	b.Add(64, 0, 63, 130)
+
+	slice := b.ToSlice()
+	expected := []int{0, 63, 64, 130}
+
+	if len(slice) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, slice)
+	}
+	for i := range expected {
+		if slice[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, slice)
+		}
+	}
+
+	count := 0
+	for range b.Iter() {
+		count++
+	}
+
+	if count != 4 {
+		t.Errorf("Expected iterator to yield 4 values, got %d", count)
+	}
+	if !b.All(func(i int) bool { return i >= 0 }) || b.Any(func(i int) bool { return i > 130 }) {
+		t.Errorf("Unexpected Any or All result")
+	}
+}
+
+func TestBitSet_Conversion(t *testing.T) {
+	s := set.NewSet[int](2, 1)
+	s.Add(5, 10, 15)
+
+	b := set.BitSetFromSet(s)
+
+	if b.String() != "{5, 10, 15}" {
+		t.Errorf("Unexpected BitSet %v", b)
+	}
+	if !b.ToSet().Equal(s) {
+		t.Errorf("Expected round trip to return an equal set")
+	}
+}
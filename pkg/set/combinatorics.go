@@ -0,0 +1,244 @@
+package set
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"iter"
+)
+
+// The generators below are lazy: each result is built only when the iterator reaches it,
+// and breaking out of the loop stops the enumeration.
+// Only the elements of the input sets are copied up front.
+
+// PowerSet returns an iterator over all the subsets of the set, from the empty set to the set itself.
+func PowerSet[T any](s *Set[T]) iter.Seq[*Set[T]] {
+	return func(yield func(*Set[T]) bool) {
+		items := s.ToSlice()
+		// included is a binary counter, where included[i] tells if items[i] is in the subset
+		included := make([]bool, len(items))
+
+		for {
+			if !yield(subset(items, included)) {
+				return
+			}
+
+			// Increment the counter, stopping after the last subset
+			i := 0
+			for i < len(included) && included[i] {
+				included[i] = false
+				i++
+			}
+			if i == len(included) {
+				return
+			}
+			included[i] = true
+		}
+	}
+}
+
+// Combinations returns an iterator over all the subsets of the set with k elements.
+func Combinations[T any](s *Set[T], k int) iter.Seq[*Set[T]] {
+	return func(yield func(*Set[T]) bool) {
+		items := s.ToSlice()
+		if k < 0 || k > len(items) {
+			return
+		}
+
+		// indices holds the positions of the chosen items, in increasing order
+		indices := make([]int, k)
+		for i := range indices {
+			indices[i] = i
+		}
+
+		for {
+			chosen := NewSet[T](uint32(k), hashmap.DefaultThreshold)
+			for _, i := range indices {
+				chosen.Add(items[i])
+			}
+			if !yield(chosen) {
+				return
+			}
+
+			// Find the rightmost index that can be moved forward
+			i := k - 1
+			for i >= 0 && indices[i] == len(items)-k+i {
+				i--
+			}
+			if i < 0 {
+				return
+			}
+
+			indices[i]++
+			for j := i + 1; j < k; j++ {
+				indices[j] = indices[j-1] + 1
+			}
+		}
+	}
+}
+
+// Permutations returns an iterator over all the orderings of the elements of the set.
+// Each ordering is a new slice, which the caller can keep.
+func Permutations[T any](s *Set[T]) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		items := s.ToSlice()
+		indices := make([]int, len(items))
+		for i := range indices {
+			indices[i] = i
+		}
+
+		for {
+			permutation := make([]T, len(items))
+			for i, j := range indices {
+				permutation[i] = items[j]
+			}
+			if !yield(permutation) {
+				return
+			}
+
+			if !nextPermutation(indices) {
+				return
+			}
+		}
+	}
+}
+
+// CartesianProduct returns an iterator over all the pairs with the first element
+// in the first set and the second element in the second set.
+func CartesianProduct[T, U any](a *Set[T], b *Set[U]) iter.Seq2[T, U] {
+	return func(yield func(T, U) bool) {
+		second := b.ToSlice()
+
+		for x := range a.Elements() {
+			for _, y := range second {
+				if !yield(x, y) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// CartesianProductN returns an iterator over all the tuples holding one element of each set,
+// in the order of the sets. Each tuple is a new slice, which the caller can keep.
+func CartesianProductN[T any](sets ...*Set[T]) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		if len(sets) == 0 {
+			return
+		}
+
+		items := make([][]T, len(sets))
+		for i, s := range sets {
+			items[i] = s.ToSlice()
+			if len(items[i]) == 0 {
+				return
+			}
+		}
+
+		// indices is a mixed-radix counter, where indices[i] is the position in items[i]
+		indices := make([]int, len(sets))
+
+		for {
+			tuple := make([]T, len(sets))
+			for i, j := range indices {
+				tuple[i] = items[i][j]
+			}
+			if !yield(tuple) {
+				return
+			}
+
+			i := len(indices) - 1
+			for i >= 0 && indices[i] == len(items[i])-1 {
+				indices[i] = 0
+				i--
+			}
+			if i < 0 {
+				return
+			}
+			indices[i]++
+		}
+	}
+}
+
+// Partitions returns an iterator over all the partitions of the set into non-empty,
+// disjoint blocks whose union is the set.
+//
+// Partitions are enumerated as restricted growth strings: blocks[i] is the block of
+// the i-th element, and is at most one more than the highest block before it.
+func Partitions[T any](s *Set[T]) iter.Seq[[]*Set[T]] {
+	return func(yield func([]*Set[T]) bool) {
+		items := s.ToSlice()
+		if len(items) == 0 {
+			yield([]*Set[T]{})
+			return
+		}
+
+		blocks := make([]int, len(items))
+		// highest[i] is the highest block among blocks[:i+1]
+		highest := make([]int, len(items))
+
+		for {
+			partition := make([]*Set[T], highest[len(items)-1]+1)
+			for i := range partition {
+				partition[i] = NewSet[T](2, hashmap.DefaultThreshold)
+			}
+			for i, b := range blocks {
+				partition[b].Add(items[i])
+			}
+			if !yield(partition) {
+				return
+			}
+
+			// Find the rightmost element that can move to a later block
+			i := len(items) - 1
+			for i > 0 && blocks[i] > highest[i-1] {
+				i--
+			}
+			if i == 0 {
+				return
+			}
+
+			blocks[i]++
+			highest[i] = max(highest[i-1], blocks[i])
+			for j := i + 1; j < len(items); j++ {
+				blocks[j] = 0
+				highest[j] = highest[i]
+			}
+		}
+	}
+}
+
+// subset returns the set of the items that are included.
+func subset[T any](items []T, included []bool) *Set[T] {
+	result := NewSet[T](2, hashmap.DefaultThreshold)
+
+	for i, ok := range included {
+		if ok {
+			result.Add(items[i])
+		}
+	}
+
+	return result
+}
+
+// nextPermutation rearranges indices into the next permutation in lexicographic order,
+// returning false if indices was the last one.
+func nextPermutation(indices []int) bool {
+	i := len(indices) - 2
+	for i >= 0 && indices[i] >= indices[i+1] {
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+
+	j := len(indices) - 1
+	for indices[j] <= indices[i] {
+		j--
+	}
+	indices[i], indices[j] = indices[j], indices[i]
+
+	for l, r := i+1, len(indices)-1; l < r; l, r = l+1, r-1 {
+		indices[l], indices[r] = indices[r], indices[l]
+	}
+
+	return true
+}
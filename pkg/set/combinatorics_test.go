@@ -0,0 +1,176 @@
+package set_test
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"testing"
+)
+
+func TestPowerSet(t *testing.T) {
+	s := set.NewSet[int](2, 1)
+	s.Add(1, 2, 3)
+
+	subsets := make([]*set.Set[int], 0)
+	for subset := range set.PowerSet(s) {
+		if !subset.Subset(s) {
+			t.Errorf("Expected %v to be a subset of %v", subset, s)
+		}
+		subsets = append(subsets, subset)
+	}
+
+	if len(subsets) != 8 {
+		t.Errorf("Expected 8 subsets, got %d", len(subsets))
+	}
+	for i := range subsets {
+		for j := range subsets[:i] {
+			if subsets[i].Equal(subsets[j]) {
+				t.Errorf("Expected distinct subsets, got %v twice", subsets[i])
+			}
+		}
+	}
+}
+
+func TestPowerSet_Empty(t *testing.T) {
+	count := 0
+	for subset := range set.PowerSet(set.NewSet[int](2, 1)) {
+		if subset.Len() != 0 {
+			t.Errorf("Expected the empty set, got %v", subset)
+		}
+		count++
+	}
+
+	if count != 1 {
+		t.Errorf("Expected 1 subset, got %d", count)
+	}
+}
+
+func TestPowerSet_EarlyTermination(t *testing.T) {
+	s := set.NewSet[int](2, 1)
+	for i := 0; i < 100; i++ {
+		s.Add(i)
+	}
+
+	count := 0
+	for range set.PowerSet(s) {
+		count++
+		if count == 10 {
+			break
+		}
+	}
+
+	if count != 10 {
+		t.Errorf("Expected 10 subsets, got %d", count)
+	}
+}
+
+func TestCombinations(t *testing.T) {
+	s := set.NewSet[int](2, 1)
+	s.Add(1, 2, 3, 4, 5)
+
+	count := 0
+	for c := range set.Combinations(s, 3) {
+		if c.Len() != 3 || !c.Subset(s) {
+			t.Errorf("Unexpected combination %v", c)
+		}
+		count++
+	}
+
+	if count != 10 {
+		t.Errorf("Expected 10 combinations, got %d", count)
+	}
+
+	for range set.Combinations(s, 6) {
+		t.Errorf("Expected no combinations larger than the set")
+	}
+}
+
+func TestPermutations(t *testing.T) {
+	s := set.NewSet[string](2, 1)
+	s.Add("a", "b", "c", "d")
+
+	seen := make(map[string]bool)
+	for p := range set.Permutations(s) {
+		key := ""
+		for _, item := range p {
+			key += item
+		}
+		seen[key] = true
+	}
+
+	if len(seen) != 24 {
+		t.Errorf("Expected 24 distinct permutations, got %d", len(seen))
+	}
+}
+
+func TestCartesianProduct(t *testing.T) {
+	a := set.NewSet[int](2, 1)
+	a.Add(1, 2)
+
+	b := set.NewSet[string](2, 1)
+	b.Add("x", "y", "z")
+
+	count := 0
+	for x, y := range set.CartesianProduct(a, b) {
+		if !a.Contains(x) || !b.Contains(y) {
+			t.Errorf("Unexpected pair (%d, %s)", x, y)
+		}
+		count++
+	}
+
+	if count != 6 {
+		t.Errorf("Expected 6 pairs, got %d", count)
+	}
+}
+
+func TestCartesianProductN(t *testing.T) {
+	a := set.NewSet[int](2, 1)
+	a.Add(1, 2)
+
+	b := set.NewSet[int](2, 1)
+	b.Add(3, 4, 5)
+
+	c := set.NewSet[int](2, 1)
+	c.Add(6)
+
+	count := 0
+	for tuple := range set.CartesianProductN(a, b, c) {
+		if len(tuple) != 3 || !a.Contains(tuple[0]) || !b.Contains(tuple[1]) || tuple[2] != 6 {
+			t.Errorf("Unexpected tuple %v", tuple)
+		}
+		count++
+	}
+
+	if count != 6 {
+		t.Errorf("Expected 6 tuples, got %d", count)
+	}
+
+	for range set.CartesianProductN(a, set.NewSet[int](2, 1)) {
+		t.Errorf("Expected no tuples with an empty set")
+	}
+}
+
+func TestPartitions(t *testing.T) {
+	s := set.NewSet[int](2, 1)
+	s.Add(1, 2, 3, 4)
+
+	count := 0
+	for partition := range set.Partitions(s) {
+		union := set.UnionAll(partition...)
+		total := 0
+		for _, block := range partition {
+			if block.Len() == 0 {
+				t.Errorf("Expected non-empty blocks, got %v", partition)
+			}
+			total += block.Len()
+		}
+
+		if !union.Equal(s) || total != s.Len() {
+			t.Errorf("Expected disjoint blocks covering the set, got %v", partition)
+		}
+		count++
+	}
+
+	// The Bell number of 4
+	if count != 15 {
+		t.Errorf("Expected 15 partitions, got %d", count)
+	}
+}
@@ -29,3 +29,34 @@ func (s *Set[T]) Subset(other *Set[T]) bool {
 
 	return true
 }
+
+// Superset checks if the set is a superset of another set.
+func (s *Set[T]) Superset(other *Set[T]) bool {
+	return other.Subset(s)
+}
+
+// ProperSubset checks if the set is a subset of another set, and is not equal to it.
+func (s *Set[T]) ProperSubset(other *Set[T]) bool {
+	return s.Len() < other.Len() && s.Subset(other)
+}
+
+// ProperSuperset checks if the set is a superset of another set, and is not equal to it.
+func (s *Set[T]) ProperSuperset(other *Set[T]) bool {
+	return other.ProperSubset(s)
+}
+
+// IsDisjoint checks if the set has no elements in common with another set.
+func (s *Set[T]) IsDisjoint(other *Set[T]) bool {
+	// Iterate over the smaller set
+	if s.Len() > other.Len() {
+		s, other = other, s
+	}
+
+	for value := range s.Elements() {
+		if other.Contains(value) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -83,3 +83,51 @@ func TestSet_Subset_False(t *testing.T) {
 		t.Errorf("Expected Subset to return false for non-subset")
 	}
 }
+
+func TestSet_Superset(t *testing.T) {
+	s1 := set.NewSet[int](2, 1)
+	s1.Add(1, 2, 3)
+
+	s2 := set.NewSet[int](2, 1)
+	s2.Add(1, 2)
+
+	if !s1.Superset(s2) || s2.Superset(s1) {
+		t.Errorf("Expected Superset to return true only for the larger set")
+	}
+}
+
+func TestSet_ProperSubset(t *testing.T) {
+	s1 := set.NewSet[int](2, 1)
+	s1.Add(1, 2)
+
+	s2 := set.NewSet[int](2, 1)
+	s2.Add(1, 2, 3)
+
+	if !s1.ProperSubset(s2) {
+		t.Errorf("Expected ProperSubset to return true for a proper subset")
+	}
+	if s1.ProperSubset(s1.Copy()) {
+		t.Errorf("Expected ProperSubset to return false for equal sets")
+	}
+	if !s2.ProperSuperset(s1) || s2.ProperSuperset(s2.Copy()) {
+		t.Errorf("Expected ProperSuperset to return true only for a proper superset")
+	}
+}
+
+func TestSet_IsDisjoint(t *testing.T) {
+	s1 := set.NewSet[int](2, 1)
+	s1.Add(1, 2)
+
+	s2 := set.NewSet[int](2, 1)
+	s2.Add(3, 4, 5)
+
+	if !s1.IsDisjoint(s2) {
+		t.Errorf("Expected IsDisjoint to return true for disjoint sets")
+	}
+
+	s2.Add(2)
+
+	if s1.IsDisjoint(s2) || s2.IsDisjoint(s1) {
+		t.Errorf("Expected IsDisjoint to return false for overlapping sets")
+	}
+}
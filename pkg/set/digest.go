@@ -0,0 +1,23 @@
+package set
+
+import "github.com/pietroagazzi/gohashlib/pkg/utils"
+
+// Digest returns a hash of the elements of the set, which does not depend on their order
+// or on the size of the set. Sets with equal elements have equal digests, and sets with
+// different elements have different digests with high probability.
+//
+// The digest is the sum of the mixed hashes of the elements.
+// It returns an error if an element cannot be encoded.
+func (s *Set[T]) Digest() (uint64, error) {
+	digest := uint64(0)
+
+	for value := range s.Elements() {
+		h, err := utils.Hash64(value)
+		if err != nil {
+			return 0, err
+		}
+		digest += utils.Mix64(h)
+	}
+
+	return digest, nil
+}
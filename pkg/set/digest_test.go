@@ -0,0 +1,27 @@
+package set_test
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"testing"
+)
+
+func TestSet_Digest(t *testing.T) {
+	s1 := set.NewSet[string](2, 0.75)
+	s1.Add("a", "b", "c")
+
+	s2 := set.NewSet[string](20, 0.75)
+	s2.Add("c", "a", "b")
+
+	d1, err := s1.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d2, _ := s2.Digest(); d1 != d2 {
+		t.Errorf("Expected equal digests, got %d and %d", d1, d2)
+	}
+
+	s2.Remove("b")
+	if d2, _ := s2.Digest(); d1 == d2 {
+		t.Errorf("Expected digests to differ after removing an element")
+	}
+}
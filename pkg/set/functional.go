@@ -0,0 +1,64 @@
+package set
+
+// Filter returns a new set with the values of the set that satisfy the predicate.
+func Filter[T any](s *Set[T], predicate func(T) bool) *Set[T] {
+	result := NewSet[T](s.Size(), s.m.Threshold)
+
+	for value := range s.Elements() {
+		if predicate(value) {
+			result.Add(value)
+		}
+	}
+
+	return result
+}
+
+// Map returns a new set with the values of the set transformed by f.
+// Values transformed into the same value are merged.
+func Map[T, U any](s *Set[T], f func(T) U) *Set[U] {
+	result := NewSet[U](s.Size(), s.m.Threshold)
+
+	for value := range s.Elements() {
+		result.Add(f(value))
+	}
+
+	return result
+}
+
+// Reduce combines all values of the set into a single value, starting from initial.
+func Reduce[T, A any](s *Set[T], initial A, f func(A, T) A) A {
+	acc := initial
+
+	for value := range s.Elements() {
+		acc = f(acc, value)
+	}
+
+	return acc
+}
+
+// Find returns a value of the set that satisfies the predicate.
+func Find[T any](s *Set[T], predicate func(T) bool) (value T, ok bool) {
+	for v := range s.Elements() {
+		if predicate(v) {
+			return v, true
+		}
+	}
+
+	return value, false
+}
+
+// Partition splits the set into the values that satisfy the predicate and the values that do not.
+func Partition[T any](s *Set[T], predicate func(T) bool) (matched, rest *Set[T]) {
+	matched = NewSet[T](s.Size(), s.m.Threshold)
+	rest = NewSet[T](s.Size(), s.m.Threshold)
+
+	for value := range s.Elements() {
+		if predicate(value) {
+			matched.Add(value)
+		} else {
+			rest.Add(value)
+		}
+	}
+
+	return matched, rest
+}
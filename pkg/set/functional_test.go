@@ -0,0 +1,60 @@
+package set_test
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	s := set.NewSet[int](2, 1)
+	s.Add(1, 2, 3, 4)
+
+	even := set.Filter(s, func(i int) bool { return i%2 == 0 })
+
+	if even.Len() != 2 || !even.Contains(2) || !even.Contains(4) {
+		t.Errorf("Expected {2, 4}, got %v", even)
+	}
+}
+
+func TestMap(t *testing.T) {
+	s := set.NewSet[int](2, 1)
+	s.Add(1, 2, 3, 4)
+
+	parity := set.Map(s, func(i int) bool { return i%2 == 0 })
+
+	if parity.Len() != 2 {
+		t.Errorf("Expected {false, true}, got %v", parity)
+	}
+}
+
+func TestReduce(t *testing.T) {
+	s := set.NewSet[int](2, 1)
+	s.Add(1, 2, 3, 4)
+
+	if sum := set.Reduce(s, 0, func(acc, i int) int { return acc + i }); sum != 10 {
+		t.Errorf("Expected sum to be 10, got %d", sum)
+	}
+}
+
+func TestFind(t *testing.T) {
+	s := set.NewSet[int](2, 1)
+	s.Add(1, 2, 3, 4)
+
+	if value, ok := set.Find(s, func(i int) bool { return i > 3 }); !ok || value != 4 {
+		t.Errorf("Expected to find 4, got %d", value)
+	}
+	if _, ok := set.Find(s, func(i int) bool { return i > 4 }); ok {
+		t.Errorf("Expected to find nothing")
+	}
+}
+
+func TestPartition(t *testing.T) {
+	s := set.NewSet[int](2, 1)
+	s.Add(1, 2, 3, 4, 5)
+
+	small, large := set.Partition(s, func(i int) bool { return i < 3 })
+
+	if small.Len() != 2 || large.Len() != 3 {
+		t.Errorf("Expected 2 and 3 values, got %v and %v", small, large)
+	}
+}
@@ -0,0 +1,93 @@
+package set
+
+import "iter"
+
+// Interface is the behavior shared by the sets of this library,
+// so that code can accept any of them.
+//
+// Set, BitSet and Roaring satisfy it.
+// Iteration uses Elements, since All is the predicate check of Set.
+type Interface[T any] interface {
+	// Add adds values to the set.
+	Add(values ...T)
+	// Remove removes a value from the set.
+	Remove(value T)
+	// Contains checks if the set contains a value.
+	Contains(value T) bool
+	// Len returns the number of values in the set.
+	Len() int
+	// Elements returns an iterator over the values in the set.
+	Elements() iter.Seq[T]
+}
+
+var (
+	_ Interface[string] = (*Set[string])(nil)
+	_ Interface[int]    = (*BitSet[int])(nil)
+	_ Interface[uint32] = (*Roaring)(nil)
+)
+
+// The algorithms below accept any Interface, so they can mix implementations.
+// T cannot be inferred from an Interface argument, so it must be given explicitly:
+//
+//	set.Equal[uint32](bitset, roaring)
+
+// Equal checks if two sets hold the same values.
+func Equal[T any](a, b Interface[T]) bool {
+	return a.Len() == b.Len() && IsSubset(a, b)
+}
+
+// IsSubset checks if every value of a is in b.
+func IsSubset[T any](a, b Interface[T]) bool {
+	if a.Len() > b.Len() {
+		return false
+	}
+
+	for value := range a.Elements() {
+		if !b.Contains(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsDisjoint checks if a and b have no values in common.
+func IsDisjoint[T any](a, b Interface[T]) bool {
+	// Iterate over the smaller set
+	if a.Len() > b.Len() {
+		a, b = b, a
+	}
+
+	for value := range a.Elements() {
+		if b.Contains(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AddAll adds to dst all the values of src.
+func AddAll[T any](dst, src Interface[T]) {
+	for value := range src.Elements() {
+		dst.Add(value)
+	}
+}
+
+// RemoveAll removes from dst all the values of src.
+func RemoveAll[T any](dst, src Interface[T]) {
+	for value := range src.Elements() {
+		dst.Remove(value)
+	}
+}
+
+// Collect returns a slice containing all values of the set.
+func Collect[T any](s Interface[T]) []T {
+	slice := make([]T, 0, s.Len())
+
+	for value := range s.Elements() {
+		slice = append(slice, value)
+	}
+
+	return slice
+}
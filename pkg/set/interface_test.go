@@ -0,0 +1,74 @@
+package set_test
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	b := set.NewBitSet[uint32](0)
+	b.Add(1, 2, 70000)
+
+	r := set.NewRoaring()
+	r.Add(70000, 2, 1)
+
+	s := set.NewSet[uint32](2, 1)
+	s.Add(1, 2)
+
+	if !set.Equal[uint32](b, r) || !set.Equal[uint32](r, b) {
+		t.Errorf("Expected BitSet and Roaring to be equal")
+	}
+	if set.Equal[uint32](s, r) {
+		t.Errorf("Expected Set and Roaring to be different")
+	}
+	if !set.IsSubset[uint32](s, r) || set.IsSubset[uint32](r, s) {
+		t.Errorf("Expected Set to be a subset of Roaring")
+	}
+}
+
+func TestIsDisjoint(t *testing.T) {
+	s := set.NewSet[int](2, 1)
+	s.Add(1, 2)
+
+	b := set.NewBitSet[int](0)
+	b.Add(3, 4, 5)
+
+	if !set.IsDisjoint[int](s, b) {
+		t.Errorf("Expected sets to be disjoint")
+	}
+
+	b.Add(2)
+
+	if set.IsDisjoint[int](s, b) {
+		t.Errorf("Expected sets to overlap")
+	}
+}
+
+func TestAddAll(t *testing.T) {
+	s := set.NewSet[int](2, 1)
+	s.Add(1, 2)
+
+	b := set.NewBitSet[int](0)
+	b.Add(2, 3)
+
+	set.AddAll[int](b, s)
+
+	if b.String() != "{1, 2, 3}" {
+		t.Errorf("Unexpected BitSet %v", b)
+	}
+
+	set.RemoveAll[int](b, s)
+
+	if b.String() != "{3}" {
+		t.Errorf("Unexpected BitSet %v", b)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	r := set.NewRoaring()
+	r.Add(3, 1, 2)
+
+	if slice := set.Collect[uint32](r); len(slice) != 3 || slice[0] != 1 {
+		t.Errorf("Expected [1 2 3], got %v", slice)
+	}
+}
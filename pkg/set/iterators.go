@@ -1,5 +1,7 @@
 package set
 
+import "iter"
+
 // Iter returns a channel that yields each value in the set.
 func (s *Set[T]) Iter() <-chan T {
 	ch := make(chan T)
@@ -11,3 +13,15 @@ func (s *Set[T]) Iter() <-chan T {
 	}()
 	return ch
 }
+
+// Elements returns an iterator over each value in the set, without the goroutine of Iter.
+// The set must not be modified during the iteration.
+func (s *Set[T]) Elements() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for value := range s.m.All() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
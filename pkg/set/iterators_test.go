@@ -27,3 +27,17 @@ func TestSet_Iter(t *testing.T) {
 		t.Errorf("Expected iterator to be closed")
 	}
 }
+
+func TestSet_Elements(t *testing.T) {
+	s := set.NewSet[int](2, 1)
+	s.Add(1, 2, 3)
+
+	sum := 0
+	for value := range s.Elements() {
+		sum += value
+	}
+
+	if sum != 6 {
+		t.Errorf("Expected sum to be 6, got %d", sum)
+	}
+}
@@ -1,21 +1,24 @@
 package set
 
-import "github.com/pietroagazzi/gohashlib/pkg/hashmap"
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"sort"
+)
 
 // Union returns a new set with all the elements that are in either set.
-func (s *Set[T]) Union(other Set[T]) *Set[T] {
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
 	result := s.Copy()
-	for item := range other.Iter() {
+	for item := range other.Elements() {
 		result.Add(item)
 	}
 	return result
 }
 
 // Intersection returns a new set with all the elements that are in both sets.
-func (s *Set[T]) Intersection(other Set[T]) *Set[T] {
+func (s *Set[T]) Intersection(other *Set[T]) *Set[T] {
 	result := NewSet[T](s.Size(), hashmap.DefaultThreshold)
 
-	for item := range s.Iter() {
+	for item := range s.Elements() {
 		if other.Contains(item) {
 			result.Add(item)
 		}
@@ -24,13 +27,139 @@ func (s *Set[T]) Intersection(other Set[T]) *Set[T] {
 }
 
 // Difference returns a new set with all the elements that are in the first set but not in the second set.
-func (s *Set[T]) Difference(other Set[T]) *Set[T] {
+func (s *Set[T]) Difference(other *Set[T]) *Set[T] {
 	result := NewSet[T](s.Size(), hashmap.DefaultThreshold)
 
-	for item := range s.Iter() {
+	for item := range s.Elements() {
 		if !other.Contains(item) {
 			result.Add(item)
 		}
 	}
 	return result
 }
+
+// SymmetricDifference returns a new set with all the elements that are in exactly one of the sets.
+func (s *Set[T]) SymmetricDifference(other *Set[T]) *Set[T] {
+	result := s.Difference(other)
+
+	for item := range other.Elements() {
+		if !s.Contains(item) {
+			result.Add(item)
+		}
+	}
+	return result
+}
+
+// UnionWith adds to the set all the elements of the other set.
+func (s *Set[T]) UnionWith(other *Set[T]) {
+	if s == other {
+		return
+	}
+
+	for item := range other.Elements() {
+		s.Add(item)
+	}
+}
+
+// IntersectWith removes from the set all the elements that are not in the other set.
+func (s *Set[T]) IntersectWith(other *Set[T]) {
+	s.removeWhere(func(item T) bool { return !other.Contains(item) })
+}
+
+// DifferenceWith removes from the set all the elements that are in the other set.
+func (s *Set[T]) DifferenceWith(other *Set[T]) {
+	if s == other {
+		s.Clear()
+		return
+	}
+
+	for item := range other.Elements() {
+		s.Remove(item)
+	}
+}
+
+// SymmetricDifferenceWith removes from the set the elements that are in the other set,
+// and adds the elements of the other set that were not in it.
+func (s *Set[T]) SymmetricDifferenceWith(other *Set[T]) {
+	if s == other {
+		s.Clear()
+		return
+	}
+
+	for item := range other.Elements() {
+		if s.Contains(item) {
+			s.Remove(item)
+		} else {
+			s.Add(item)
+		}
+	}
+}
+
+// removeWhere removes from the set all the elements that satisfy the predicate.
+// The elements are collected first, since the set must not change while it is iterated.
+func (s *Set[T]) removeWhere(predicate func(T) bool) {
+	var removed []T
+
+	for item := range s.Elements() {
+		if predicate(item) {
+			removed = append(removed, item)
+		}
+	}
+
+	for _, item := range removed {
+		s.Remove(item)
+	}
+}
+
+// UnionAll returns a new set with all the elements that are in any of the sets.
+func UnionAll[T any](sets ...*Set[T]) *Set[T] {
+	if len(sets) == 0 {
+		return NewSet[T](0, hashmap.DefaultThreshold)
+	}
+
+	// Start from the largest set, so that it is copied instead of added element by element
+	largest := sets[0]
+	for _, s := range sets[1:] {
+		if s.Len() > largest.Len() {
+			largest = s
+		}
+	}
+
+	result := largest.Copy()
+	for _, s := range sets {
+		if s != largest {
+			result.UnionWith(s)
+		}
+	}
+	return result
+}
+
+// IntersectAll returns a new set with all the elements that are in every set.
+//
+// The sets are checked from the smallest to the largest: only the elements of the
+// smallest set are candidates, and most of them are discarded by the smaller sets.
+func IntersectAll[T any](sets ...*Set[T]) *Set[T] {
+	if len(sets) == 0 {
+		return NewSet[T](0, hashmap.DefaultThreshold)
+	}
+
+	sorted := append([]*Set[T](nil), sets...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Len() < sorted[j].Len() })
+
+	result := NewSet[T](sorted[0].Size(), hashmap.DefaultThreshold)
+
+	for item := range sorted[0].Elements() {
+		found := true
+		for _, s := range sorted[1:] {
+			if !s.Contains(item) {
+				found = false
+				break
+			}
+		}
+
+		if found {
+			result.Add(item)
+		}
+	}
+	return result
+}
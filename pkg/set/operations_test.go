@@ -12,7 +12,7 @@ func TestSet_Union(t *testing.T) {
 	s2 := set.NewSet[int](2, 1)
 	s2.Add(3, 4, 5)
 
-	union := s1.Union(*s2)
+	union := s1.Union(s2)
 	elements := map[int]bool{1: false, 2: false, 3: false, 4: false, 5: false}
 
 	for item := range union.Iter() {
@@ -36,7 +36,7 @@ func TestSet_Intersection(t *testing.T) {
 	s2 := set.NewSet[int](2, 1)
 	s2.Add(3, 4, 5)
 
-	intersection := s1.Intersection(*s2)
+	intersection := s1.Intersection(s2)
 
 	if intersection.Len() != 1 || !intersection.Contains(3) {
 		t.Errorf("Intersection did not return the expected element: %v", intersection)
@@ -50,9 +50,126 @@ func TestSet_Difference(t *testing.T) {
 	s2 := set.NewSet[int](2, 1)
 	s2.Add(3, 4, 5)
 
-	difference := s1.Difference(*s2)
+	difference := s1.Difference(s2)
 
 	if difference.Len() != 2 || !difference.Contains(1) || !difference.Contains(2) {
 		t.Errorf("Difference did not return the expected elements: %v", difference)
 	}
 }
+
+func TestSet_SymmetricDifference(t *testing.T) {
+	s1 := set.NewSet[int](2, 1)
+	s1.Add(1, 2, 3)
+
+	s2 := set.NewSet[int](2, 1)
+	s2.Add(3, 4, 5)
+
+	difference := s1.SymmetricDifference(s2)
+
+	if difference.Len() != 4 || difference.Contains(3) || !difference.Contains(1) || !difference.Contains(5) {
+		t.Errorf("SymmetricDifference did not return the expected elements: %v", difference)
+	}
+}
+
+func TestSet_UnionWith(t *testing.T) {
+	s1 := set.NewSet[int](2, 1)
+	s1.Add(1, 2)
+
+	s2 := set.NewSet[int](2, 1)
+	s2.Add(2, 3)
+
+	s1.UnionWith(s2)
+	s1.UnionWith(s1)
+
+	if s1.Len() != 3 || !s1.Contains(3) {
+		t.Errorf("UnionWith did not add the expected elements: %v", s1)
+	}
+}
+
+func TestSet_IntersectWith(t *testing.T) {
+	s1 := set.NewSet[int](2, 1)
+	s1.Add(1, 2, 3, 4)
+
+	s2 := set.NewSet[int](2, 1)
+	s2.Add(2, 4, 6)
+
+	s1.IntersectWith(s2)
+
+	if s1.Len() != 2 || !s1.Contains(2) || !s1.Contains(4) {
+		t.Errorf("IntersectWith did not keep the expected elements: %v", s1)
+	}
+}
+
+func TestSet_DifferenceWith(t *testing.T) {
+	s1 := set.NewSet[int](2, 1)
+	s1.Add(1, 2, 3)
+
+	s2 := set.NewSet[int](2, 1)
+	s2.Add(2, 5)
+
+	s1.DifferenceWith(s2)
+
+	if s1.Len() != 2 || s1.Contains(2) {
+		t.Errorf("DifferenceWith did not remove the expected elements: %v", s1)
+	}
+
+	s1.DifferenceWith(s1)
+
+	if s1.Len() != 0 {
+		t.Errorf("Expected the difference of a set with itself to be empty: %v", s1)
+	}
+}
+
+func TestSet_SymmetricDifferenceWith(t *testing.T) {
+	s1 := set.NewSet[int](2, 1)
+	s1.Add(1, 2, 3)
+
+	s2 := set.NewSet[int](2, 1)
+	s2.Add(3, 4)
+
+	s1.SymmetricDifferenceWith(s2)
+
+	if s1.Len() != 3 || s1.Contains(3) || !s1.Contains(4) {
+		t.Errorf("SymmetricDifferenceWith did not return the expected elements: %v", s1)
+	}
+}
+
+func TestUnionAll(t *testing.T) {
+	s1 := set.NewSet[int](2, 1)
+	s1.Add(1, 2)
+
+	s2 := set.NewSet[int](2, 1)
+	s2.Add(2, 3, 4)
+
+	s3 := set.NewSet[int](2, 1)
+	s3.Add(5)
+
+	union := set.UnionAll(s1, s2, s3)
+
+	if union.Len() != 5 {
+		t.Errorf("UnionAll did not return the expected elements: %v", union)
+	}
+	if set.UnionAll[int]().Len() != 0 {
+		t.Errorf("Expected the union of no sets to be empty")
+	}
+}
+
+func TestIntersectAll(t *testing.T) {
+	s1 := set.NewSet[int](2, 1)
+	s1.Add(1, 2, 3, 4)
+
+	s2 := set.NewSet[int](2, 1)
+	s2.Add(2, 3, 4, 5)
+
+	s3 := set.NewSet[int](2, 1)
+	s3.Add(3, 4)
+
+	intersection := set.IntersectAll(s1, s2, s3)
+
+	if intersection.Len() != 2 || !intersection.Contains(3) || !intersection.Contains(4) {
+		t.Errorf("IntersectAll did not return the expected elements: %v", intersection)
+	}
+	if set.IntersectAll[int]().Len() != 0 {
+		t.Errorf("Expected the intersection of no sets to be empty")
+	}
+}
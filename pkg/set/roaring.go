@@ -0,0 +1,456 @@
+package set
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"iter"
+	"slices"
+)
+
+// Roaring represents a compressed set of uint32 values, in the style of Roaring bitmaps.
+// https://roaringbitmap.org
+//
+// Values are split into chunks by their high 16 bits. Each chunk keeps its low 16 bits
+// in the most compact container: a sorted array for sparse chunks, a bitmap for dense
+// chunks, or a list of runs for chunks made of long intervals (see RunOptimize).
+// Set operations work chunk by chunk, skipping the chunks that only one side has.
+//
+// Roaring has the same methods as Set[uint32], so algorithms can accept either.
+type Roaring struct {
+	// keys are the high 16 bits of the chunks, in increasing order
+	keys []uint16
+	// containers are the low 16 bits of the values of each chunk
+	containers []container
+}
+
+// NewRoaring creates a new empty Roaring.
+func NewRoaring() *Roaring {
+	return &Roaring{}
+}
+
+// split returns the high and the low 16 bits of a value.
+func split(value uint32) (high, low uint16) {
+	return uint16(value >> 16), uint16(value)
+}
+
+// find returns the position of the chunk with the given key, or where it should be inserted.
+func (r *Roaring) find(key uint16) (int, bool) {
+	return slices.BinarySearch(r.keys, key)
+}
+
+// Add adds values to the Roaring.
+func (r *Roaring) Add(values ...uint32) {
+	for _, v := range values {
+		high, low := split(v)
+
+		i, found := r.find(high)
+		if !found {
+			r.keys = slices.Insert(r.keys, i, high)
+			r.containers = slices.Insert(r.containers, i, container(arrayContainer{low}))
+			continue
+		}
+
+		r.containers[i] = r.containers[i].add(low)
+	}
+}
+
+// Remove removes a value from the Roaring.
+func (r *Roaring) Remove(value uint32) {
+	high, low := split(value)
+
+	i, found := r.find(high)
+	if !found {
+		return
+	}
+
+	r.containers[i] = r.containers[i].remove(low)
+
+	// Drop the empty chunks
+	if r.containers[i].cardinality() == 0 {
+		r.keys = slices.Delete(r.keys, i, i+1)
+		r.containers = slices.Delete(r.containers, i, i+1)
+	}
+}
+
+// Contains checks if the Roaring contains a value.
+func (r *Roaring) Contains(value uint32) bool {
+	high, low := split(value)
+
+	i, found := r.find(high)
+	return found && r.containers[i].contains(low)
+}
+
+// Size returns the number of chunks of the Roaring.
+func (r *Roaring) Size() uint32 {
+	return uint32(len(r.keys))
+}
+
+// Len returns the number of values in the Roaring.
+func (r *Roaring) Len() int {
+	count := 0
+	for _, c := range r.containers {
+		count += c.cardinality()
+	}
+	return count
+}
+
+// Clear removes all values from the Roaring.
+func (r *Roaring) Clear() {
+	r.keys = nil
+	r.containers = nil
+}
+
+// Copy returns a copy of the Roaring.
+func (r *Roaring) Copy() *Roaring {
+	result := &Roaring{keys: slices.Clone(r.keys), containers: make([]container, len(r.containers))}
+	for i, c := range r.containers {
+		result.containers[i] = c.clone()
+	}
+	return result
+}
+
+// RunOptimize converts the chunks made of long intervals into run containers,
+// when it makes them smaller. It is meant to be called once the Roaring is built,
+// since modifying a run container converts it back.
+func (r *Roaring) RunOptimize() {
+	for i, c := range r.containers {
+		if runs := toRuns(c); sizeInBytes(runs) < sizeInBytes(c) {
+			r.containers[i] = runs
+		}
+	}
+}
+
+// Union returns a new Roaring with all the values that are in either Roaring.
+func (r *Roaring) Union(other *Roaring) *Roaring {
+	result := NewRoaring()
+	i, j := 0, 0
+
+	for i < len(r.keys) || j < len(other.keys) {
+		switch {
+		case j == len(other.keys) || i < len(r.keys) && r.keys[i] < other.keys[j]:
+			result.append(r.keys[i], r.containers[i].clone())
+			i++
+		case i == len(r.keys) || other.keys[j] < r.keys[i]:
+			result.append(other.keys[j], other.containers[j].clone())
+			j++
+		default:
+			result.append(r.keys[i], unionContainers(r.containers[i], other.containers[j]))
+			i++
+			j++
+		}
+	}
+
+	return result
+}
+
+// Intersection returns a new Roaring with all the values that are in both Roarings.
+func (r *Roaring) Intersection(other *Roaring) *Roaring {
+	result := NewRoaring()
+
+	for i, key := range r.keys {
+		if j, found := other.find(key); found {
+			result.append(key, intersectContainers(r.containers[i], other.containers[j]))
+		}
+	}
+
+	return result
+}
+
+// Difference returns a new Roaring with all the values that are in the first Roaring but not in the second.
+func (r *Roaring) Difference(other *Roaring) *Roaring {
+	result := NewRoaring()
+
+	for i, key := range r.keys {
+		if j, found := other.find(key); found {
+			result.append(key, differenceContainers(r.containers[i], other.containers[j]))
+		} else {
+			result.append(key, r.containers[i].clone())
+		}
+	}
+
+	return result
+}
+
+// SymmetricDifference returns a new Roaring with all the values that are in exactly one of the Roarings.
+func (r *Roaring) SymmetricDifference(other *Roaring) *Roaring {
+	return r.Union(other).Difference(r.Intersection(other))
+}
+
+// append adds a chunk after the last one, unless it is empty.
+func (r *Roaring) append(key uint16, c container) {
+	if c.cardinality() == 0 {
+		return
+	}
+	r.keys = append(r.keys, key)
+	r.containers = append(r.containers, c)
+}
+
+// Equal checks if two Roarings hold the same values.
+func (r *Roaring) Equal(other *Roaring) bool {
+	return r.Len() == other.Len() && r.Subset(other)
+}
+
+// Subset checks if the Roaring is a subset of another Roaring.
+func (r *Roaring) Subset(other *Roaring) bool {
+	for i, key := range r.keys {
+		j, found := other.find(key)
+		if !found || differenceContainers(r.containers[i], other.containers[j]).cardinality() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Any checks if any value in the Roaring satisfies the callback.
+func (r *Roaring) Any(callback func(uint32) bool) bool {
+	for value := range r.Elements() {
+		if callback(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// All checks if all values in the Roaring satisfy the callback.
+func (r *Roaring) All(callback func(uint32) bool) bool {
+	for value := range r.Elements() {
+		if !callback(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Elements returns an iterator over the values in the Roaring, in increasing order.
+func (r *Roaring) Elements() iter.Seq[uint32] {
+	return func(yield func(uint32) bool) {
+		for i, c := range r.containers {
+			high := uint32(r.keys[i]) << 16
+			if !c.each(func(low uint16) bool { return yield(high | uint32(low)) }) {
+				return
+			}
+		}
+	}
+}
+
+// Iter returns a channel that yields each value in the Roaring, in increasing order.
+func (r *Roaring) Iter() <-chan uint32 {
+	ch := make(chan uint32)
+	go func() {
+		for value := range r.Elements() {
+			ch <- value
+		}
+		close(ch)
+	}()
+	return ch
+}
+
+// ToSlice returns a slice containing all values in the Roaring, in increasing order.
+func (r *Roaring) ToSlice() []uint32 {
+	slice := make([]uint32, 0, r.Len())
+	for value := range r.Elements() {
+		slice = append(slice, value)
+	}
+	return slice
+}
+
+// ToSet returns a Set containing all values in the Roaring.
+func (r *Roaring) ToSet() *Set[uint32] {
+	s := NewSet[uint32](uint32(r.Len()), hashmap.DefaultThreshold)
+	for value := range r.Elements() {
+		s.Add(value)
+	}
+	return s
+}
+
+// RoaringFromSet returns a Roaring containing all values in the set.
+func RoaringFromSet(s *Set[uint32]) *Roaring {
+	values := s.ToSlice()
+	slices.Sort(values)
+
+	r := NewRoaring()
+	r.Add(values...)
+	return r
+}
+
+// String returns a string representation of the Roaring.
+func (r *Roaring) String() string {
+	out := "{"
+
+	for value := range r.Elements() {
+		out += fmt.Sprintf("%v, ", value)
+	}
+
+	if len(out) > 1 {
+		out = out[:len(out)-2]
+	}
+
+	return out + "}"
+}
+
+// ErrInvalidRoaring is returned when decoding bytes that are not an encoded Roaring.
+var ErrInvalidRoaring = errors.New("set: invalid Roaring encoding")
+
+// roaringMagic starts every encoded Roaring.
+const roaringMagic = "RBM1"
+
+// Container kinds in the encoding.
+const (
+	arrayKind byte = iota
+	bitmapKind
+	runKind
+)
+
+// MarshalBinary encodes the Roaring into bytes.
+//
+// The encoding starts with a magic string and the number of chunks, followed by each
+// chunk: its key, its container kind, the number of items and the items themselves.
+// All integers are little-endian.
+func (r *Roaring) MarshalBinary() ([]byte, error) {
+	out := []byte(roaringMagic)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(r.keys)))
+
+	for i, c := range r.containers {
+		out = binary.LittleEndian.AppendUint16(out, r.keys[i])
+
+		switch c := c.(type) {
+		case arrayContainer:
+			out = append(out, arrayKind)
+			out = binary.LittleEndian.AppendUint32(out, uint32(len(c)))
+			for _, x := range c {
+				out = binary.LittleEndian.AppendUint16(out, x)
+			}
+		case *bitmapContainer:
+			out = append(out, bitmapKind)
+			out = binary.LittleEndian.AppendUint32(out, uint32(len(c.words)))
+			for _, w := range c.words {
+				out = binary.LittleEndian.AppendUint64(out, w)
+			}
+		case runContainer:
+			out = append(out, runKind)
+			out = binary.LittleEndian.AppendUint32(out, uint32(len(c)))
+			for _, iv := range c {
+				out = binary.LittleEndian.AppendUint16(out, iv.start)
+				out = binary.LittleEndian.AppendUint16(out, iv.last)
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// UnmarshalBinary decodes bytes produced by MarshalBinary, replacing the content of the Roaring.
+func (r *Roaring) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+
+	if string(d.next(len(roaringMagic))) != roaringMagic {
+		return ErrInvalidRoaring
+	}
+
+	count := d.uint32()
+	result := Roaring{}
+
+	for range min(count, uint32(len(data))) {
+		key := d.uint16()
+		kind := d.next(1)
+		n := d.uint32()
+		if d.err || len(kind) == 0 || len(result.keys) > 0 && key <= result.keys[len(result.keys)-1] {
+			return ErrInvalidRoaring
+		}
+
+		var c container
+		switch kind[0] {
+		case arrayKind:
+			if n == 0 || n > arrayMaxSize || int(n)*2 > len(d.data) {
+				return ErrInvalidRoaring
+			}
+			a := make(arrayContainer, n)
+			for i := range a {
+				a[i] = d.uint16()
+				if i > 0 && a[i] <= a[i-1] {
+					return ErrInvalidRoaring
+				}
+			}
+			c = a
+		case bitmapKind:
+			b := &bitmapContainer{}
+			if n != uint32(len(b.words)) {
+				return ErrInvalidRoaring
+			}
+			for i := range b.words {
+				b.words[i] = d.uint64()
+			}
+			b.count()
+			c = b
+		case runKind:
+			if n == 0 || int(n)*4 > len(d.data) {
+				return ErrInvalidRoaring
+			}
+			runs := make(runContainer, n)
+			for i := range runs {
+				runs[i] = interval{d.uint16(), d.uint16()}
+				if runs[i].last < runs[i].start || i > 0 && int(runs[i].start) <= int(runs[i-1].last)+1 {
+					return ErrInvalidRoaring
+				}
+			}
+			c = runs
+		default:
+			return ErrInvalidRoaring
+		}
+
+		if d.err || c.cardinality() == 0 {
+			return ErrInvalidRoaring
+		}
+
+		result.keys = append(result.keys, key)
+		result.containers = append(result.containers, c)
+	}
+
+	if d.err || len(result.keys) != int(count) || len(d.data) != 0 {
+		return ErrInvalidRoaring
+	}
+
+	*r = result
+	return nil
+}
+
+// decoder reads little-endian integers from bytes, recording if it runs out of them.
+type decoder struct {
+	data []byte
+	err  bool
+}
+
+// next returns the next n bytes.
+func (d *decoder) next(n int) []byte {
+	if len(d.data) < n {
+		d.err = true
+		d.data = nil
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *decoder) uint16() uint16 {
+	if b := d.next(2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (d *decoder) uint32() uint32 {
+	if b := d.next(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (d *decoder) uint64() uint64 {
+	if b := d.next(8); b != nil {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
@@ -0,0 +1,324 @@
+package set
+
+import (
+	"math/bits"
+	"slices"
+	"sort"
+)
+
+// arrayMaxSize is the largest cardinality of an array container.
+// Above it, a bitmap container takes less memory.
+const arrayMaxSize = 4096
+
+// container holds the low 16 bits of the values of a Roaring sharing the same high 16 bits.
+//
+// Containers returned by add and remove may be of a different type than the receiver,
+// so that each chunk always uses the most compact of the array and bitmap representations.
+type container interface {
+	add(x uint16) container
+	remove(x uint16) container
+	contains(x uint16) bool
+	cardinality() int
+	// each calls yield for each value in increasing order, returning false if yield did
+	each(yield func(uint16) bool) bool
+	toBitmap() *bitmapContainer
+	clone() container
+}
+
+// arrayContainer is a sorted slice of values, used for sparse chunks.
+type arrayContainer []uint16
+
+func (a arrayContainer) add(x uint16) container {
+	i, found := slices.BinarySearch(a, x)
+	if found {
+		return a
+	}
+	if len(a) == arrayMaxSize {
+		return a.toBitmap().add(x)
+	}
+	return arrayContainer(slices.Insert(a, i, x))
+}
+
+func (a arrayContainer) remove(x uint16) container {
+	i, found := slices.BinarySearch(a, x)
+	if !found {
+		return a
+	}
+	return slices.Delete(a, i, i+1)
+}
+
+func (a arrayContainer) contains(x uint16) bool {
+	_, found := slices.BinarySearch(a, x)
+	return found
+}
+
+func (a arrayContainer) cardinality() int { return len(a) }
+
+func (a arrayContainer) each(yield func(uint16) bool) bool {
+	for _, x := range a {
+		if !yield(x) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a arrayContainer) toBitmap() *bitmapContainer {
+	b := &bitmapContainer{}
+	for _, x := range a {
+		b.words[x/64] |= 1 << (x % 64)
+	}
+	b.n = len(a)
+	return b
+}
+
+func (a arrayContainer) clone() container { return slices.Clone(a) }
+
+// bitmapContainer is a bitmap of the 65536 values of a chunk, used for dense chunks.
+type bitmapContainer struct {
+	words [1024]uint64
+	// n is the cardinality, kept up to date to avoid counting the bits
+	n int
+}
+
+func (b *bitmapContainer) add(x uint16) container {
+	if !b.contains(x) {
+		b.words[x/64] |= 1 << (x % 64)
+		b.n++
+	}
+	return b
+}
+
+func (b *bitmapContainer) remove(x uint16) container {
+	if !b.contains(x) {
+		return b
+	}
+	b.words[x/64] &^= 1 << (x % 64)
+	b.n--
+	return normalize(b)
+}
+
+func (b *bitmapContainer) contains(x uint16) bool {
+	return b.words[x/64]&(1<<(x%64)) != 0
+}
+
+func (b *bitmapContainer) cardinality() int { return b.n }
+
+func (b *bitmapContainer) each(yield func(uint16) bool) bool {
+	for i, w := range b.words {
+		for w != 0 {
+			if !yield(uint16(i*64 + bits.TrailingZeros64(w))) {
+				return false
+			}
+			w &= w - 1
+		}
+	}
+	return true
+}
+
+func (b *bitmapContainer) toBitmap() *bitmapContainer { return b }
+
+func (b *bitmapContainer) clone() container {
+	c := *b
+	return &c
+}
+
+// count recomputes the cardinality from the bits.
+func (b *bitmapContainer) count() {
+	b.n = 0
+	for _, w := range b.words {
+		b.n += bits.OnesCount64(w)
+	}
+}
+
+// interval is an inclusive range of values.
+type interval struct {
+	start, last uint16
+}
+
+// runContainer is a sorted slice of disjoint intervals, used for chunks made of long runs.
+// It is only built by Roaring.RunOptimize, and is replaced by an array or a bitmap when modified.
+type runContainer []interval
+
+func (r runContainer) add(x uint16) container {
+	if r.contains(x) {
+		return r
+	}
+	return normalize(r.toBitmap()).add(x)
+}
+
+func (r runContainer) remove(x uint16) container {
+	if !r.contains(x) {
+		return r
+	}
+	return normalize(r.toBitmap()).remove(x)
+}
+
+func (r runContainer) contains(x uint16) bool {
+	// Find the first interval ending at or after x
+	i := sort.Search(len(r), func(i int) bool { return r[i].last >= x })
+	return i < len(r) && r[i].start <= x
+}
+
+func (r runContainer) cardinality() int {
+	n := 0
+	for _, iv := range r {
+		n += int(iv.last-iv.start) + 1
+	}
+	return n
+}
+
+func (r runContainer) each(yield func(uint16) bool) bool {
+	for _, iv := range r {
+		for x := int(iv.start); x <= int(iv.last); x++ {
+			if !yield(uint16(x)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (r runContainer) toBitmap() *bitmapContainer {
+	b := &bitmapContainer{}
+	for _, iv := range r {
+		for x := int(iv.start); x <= int(iv.last); x++ {
+			b.words[x/64] |= 1 << (x % 64)
+		}
+	}
+	b.count()
+	return b
+}
+
+func (r runContainer) clone() container { return slices.Clone(r) }
+
+// normalize returns c in its most compact representation between array and bitmap.
+// Run containers are returned as they are.
+func normalize(c container) container {
+	switch c := c.(type) {
+	case *bitmapContainer:
+		if c.n <= arrayMaxSize {
+			a := make(arrayContainer, 0, c.n)
+			c.each(func(x uint16) bool {
+				a = append(a, x)
+				return true
+			})
+			return a
+		}
+	case arrayContainer:
+		if len(c) > arrayMaxSize {
+			return c.toBitmap()
+		}
+	}
+	return c
+}
+
+// toRuns returns the intervals of the values of c.
+func toRuns(c container) runContainer {
+	var r runContainer
+	c.each(func(x uint16) bool {
+		if len(r) > 0 && int(r[len(r)-1].last)+1 == int(x) {
+			r[len(r)-1].last = x
+		} else {
+			r = append(r, interval{x, x})
+		}
+		return true
+	})
+	return r
+}
+
+// sizeInBytes returns the serialized size of the payload of c.
+func sizeInBytes(c container) int {
+	switch c := c.(type) {
+	case arrayContainer:
+		return 2 * len(c)
+	case runContainer:
+		return 4 * len(c)
+	}
+	return 8 * 1024
+}
+
+// unionContainers returns a new container with the values of either container.
+func unionContainers(a, b container) container {
+	if x, ok := a.(arrayContainer); ok {
+		if y, ok := b.(arrayContainer); ok {
+			return normalize(mergeArrays(x, y))
+		}
+	}
+
+	x, y := a.toBitmap(), b.toBitmap()
+	result := &bitmapContainer{}
+	for i := range result.words {
+		result.words[i] = x.words[i] | y.words[i]
+	}
+	result.count()
+	return normalize(result)
+}
+
+// intersectContainers returns a new container with the values of both containers.
+func intersectContainers(a, b container) container {
+	if x, ok := a.(arrayContainer); ok {
+		return filterArray(x, b, true)
+	}
+	if y, ok := b.(arrayContainer); ok {
+		return filterArray(y, a, true)
+	}
+
+	x, y := a.toBitmap(), b.toBitmap()
+	result := &bitmapContainer{}
+	for i := range result.words {
+		result.words[i] = x.words[i] & y.words[i]
+	}
+	result.count()
+	return normalize(result)
+}
+
+// differenceContainers returns a new container with the values of a that are not in b.
+func differenceContainers(a, b container) container {
+	if x, ok := a.(arrayContainer); ok {
+		return filterArray(x, b, false)
+	}
+
+	x, y := a.toBitmap(), b.toBitmap()
+	result := &bitmapContainer{}
+	for i := range result.words {
+		result.words[i] = x.words[i] &^ y.words[i]
+	}
+	result.count()
+	return normalize(result)
+}
+
+// filterArray returns the values of a that are, or are not, in c.
+func filterArray(a arrayContainer, c container, keep bool) container {
+	result := make(arrayContainer, 0, len(a))
+	for _, x := range a {
+		if c.contains(x) == keep {
+			result = append(result, x)
+		}
+	}
+	return result
+}
+
+// mergeArrays returns the sorted values of either array.
+func mergeArrays(a, b arrayContainer) arrayContainer {
+	result := make(arrayContainer, 0, len(a)+len(b))
+	i, j := 0, 0
+
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			result = append(result, a[i])
+			i++
+		case b[j] < a[i]:
+			result = append(result, b[j])
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+
+	result = append(result, a[i:]...)
+	return append(result, b[j:]...)
+}
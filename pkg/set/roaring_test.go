@@ -0,0 +1,242 @@
+package set_test
+
+import (
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"math/rand"
+	"testing"
+)
+
+// randomRoaring returns a Roaring mixing sparse, dense and run chunks, along with its values.
+func randomRoaring(rng *rand.Rand) (*set.Roaring, map[uint32]bool) {
+	r := set.NewRoaring()
+	values := make(map[uint32]bool)
+
+	add := func(v uint32) {
+		r.Add(v)
+		values[v] = true
+	}
+
+	// Sparse values across many chunks
+	for i := 0; i < 500; i++ {
+		add(rng.Uint32())
+	}
+	// A dense chunk
+	for i := 0; i < 10000; i++ {
+		add(1<<16 | uint32(rng.Intn(1<<16)))
+	}
+	// Long runs
+	start := uint32(rng.Intn(1000))
+	for v := start; v < start+20000; v++ {
+		add(3<<16 | v)
+	}
+
+	return r, values
+}
+
+// checkRoaring reports the differences between a Roaring and the expected values.
+func checkRoaring(t *testing.T, name string, r *set.Roaring, expected map[uint32]bool) {
+	t.Helper()
+
+	if r.Len() != len(expected) {
+		t.Errorf("%s: expected length to be %d, got %d", name, len(expected), r.Len())
+	}
+
+	previous, first := uint32(0), true
+	for v := range r.Elements() {
+		if !expected[v] {
+			t.Errorf("%s: unexpected value %d", name, v)
+			return
+		}
+		if !first && v <= previous {
+			t.Errorf("%s: expected values in increasing order, got %d after %d", name, v, previous)
+			return
+		}
+		previous, first = v, false
+	}
+}
+
+func TestRoaring_Add(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	r, values := randomRoaring(rng)
+
+	checkRoaring(t, "add", r, values)
+
+	for v := range values {
+		if !r.Contains(v) {
+			t.Fatalf("Expected Roaring to contain %d", v)
+		}
+	}
+	if r.Contains(2<<16 | 5) {
+		t.Errorf("Expected Roaring to not contain a value of an empty chunk")
+	}
+}
+
+func TestRoaring_Remove(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	r, values := randomRoaring(rng)
+	r.RunOptimize()
+
+	for v := range values {
+		if rng.Intn(3) == 0 {
+			r.Remove(v)
+			delete(values, v)
+		}
+	}
+	r.Remove(2<<16 | 5)
+
+	checkRoaring(t, "remove", r, values)
+
+	for v := range values {
+		r.Remove(v)
+	}
+
+	if r.Len() != 0 || r.Size() != 0 {
+		t.Errorf("Expected empty chunks to be dropped, got %d chunks", r.Size())
+	}
+}
+
+func TestRoaring_Operations(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	r1, v1 := randomRoaring(rng)
+	r2, v2 := randomRoaring(rng)
+	r2.RunOptimize()
+
+	union, intersection, difference, symmetric := map[uint32]bool{}, map[uint32]bool{}, map[uint32]bool{}, map[uint32]bool{}
+	for v := range v1 {
+		union[v] = true
+		if v2[v] {
+			intersection[v] = true
+		} else {
+			difference[v] = true
+			symmetric[v] = true
+		}
+	}
+	for v := range v2 {
+		union[v] = true
+		if !v1[v] {
+			symmetric[v] = true
+		}
+	}
+
+	checkRoaring(t, "union", r1.Union(r2), union)
+	checkRoaring(t, "intersection", r1.Intersection(r2), intersection)
+	checkRoaring(t, "difference", r1.Difference(r2), difference)
+	checkRoaring(t, "symmetric difference", r1.SymmetricDifference(r2), symmetric)
+
+	// The operands are left unchanged
+	checkRoaring(t, "first operand", r1, v1)
+	checkRoaring(t, "second operand", r2, v2)
+}
+
+func TestRoaring_Comparisons(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	r1, _ := randomRoaring(rng)
+
+	r2 := r1.Copy()
+	r2.RunOptimize()
+
+	if !r1.Equal(r2) || !r2.Equal(r1) {
+		t.Errorf("Expected copy to be equal")
+	}
+
+	r2.Add(2 << 16)
+
+	if r1.Equal(r2) || !r1.Subset(r2) || r2.Subset(r1) {
+		t.Errorf("Expected %d values to be a subset of %d values", r1.Len(), r2.Len())
+	}
+}
+
+func TestRoaring_RunOptimize(t *testing.T) {
+	r := set.NewRoaring()
+	for v := uint32(0); v < 100000; v++ {
+		r.Add(v)
+	}
+
+	before, _ := r.MarshalBinary()
+	r.RunOptimize()
+	after, _ := r.MarshalBinary()
+
+	if len(after) >= len(before) {
+		t.Errorf("Expected runs to shrink the encoding, got %d bytes from %d", len(after), len(before))
+	}
+	if r.Len() != 100000 || !r.Contains(99999) || r.Contains(100000) {
+		t.Errorf("Expected RunOptimize to keep the values")
+	}
+
+	r.Add(200000)
+	r.Remove(50)
+
+	if r.Len() != 100000 || r.Contains(50) {
+		t.Errorf("Expected run containers to be modifiable")
+	}
+}
+
+func TestRoaring_MarshalBinary(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	r, values := randomRoaring(rng)
+	r.RunOptimize()
+
+	data, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := set.NewRoaring()
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	checkRoaring(t, "decoded", decoded, values)
+
+	for _, corrupt := range [][]byte{nil, []byte("RBM1"), data[:len(data)-1], append(data, 0)} {
+		if err := decoded.UnmarshalBinary(corrupt); !errors.Is(err, set.ErrInvalidRoaring) {
+			t.Errorf("Expected ErrInvalidRoaring decoding %d bytes, got %v", len(corrupt), err)
+		}
+	}
+}
+
+func TestRoaring_Conversion(t *testing.T) {
+	s := set.NewSet[uint32](2, 1)
+	s.Add(3, 1, 70000)
+
+	r := set.RoaringFromSet(s)
+
+	if r.String() != "{1, 3, 70000}" {
+		t.Errorf("Unexpected Roaring %v", r)
+	}
+	if !r.ToSet().Equal(s) {
+		t.Errorf("Expected round trip to return an equal set")
+	}
+	if len(r.ToSlice()) != 3 || !r.All(func(v uint32) bool { return v > 0 }) || r.Any(func(v uint32) bool { return v == 2 }) {
+		t.Errorf("Unexpected queries result")
+	}
+
+	count := 0
+	for range r.Iter() {
+		count++
+	}
+
+	r.Clear()
+
+	if count != 3 || r.Len() != 0 {
+		t.Errorf("Expected 3 values before Clear and none after")
+	}
+}
+
+func TestRoaring_Union_Compact(t *testing.T) {
+	runs := set.NewRoaring()
+	for v := uint32(0); v < 10; v++ {
+		runs.Add(v)
+	}
+	runs.RunOptimize()
+
+	sparse := set.NewRoaring()
+	sparse.Add(20)
+
+	// A small union of a run container is an array, not a bitmap of 8 KiB
+	data, _ := runs.Union(sparse).MarshalBinary()
+	if len(data) > 64 {
+		t.Errorf("Expected a compact union, got %d bytes", len(data))
+	}
+}
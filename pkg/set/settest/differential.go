@@ -0,0 +1,156 @@
+package settest
+
+import (
+	"fmt"
+	"github.com/pietroagazzi/gohashlib/pkg/internal/shrink"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"github.com/pietroagazzi/gohashlib/pkg/utils"
+	"math/rand"
+	"strings"
+)
+
+// OpKind is the kind of an operation on a set.
+type OpKind int
+
+const (
+	OpAdd OpKind = iota
+	OpContains
+	OpRemove
+)
+
+// Op is an operation on a set.
+// Values are referred to by their index, as passed to the value function.
+type Op struct {
+	Kind  OpKind
+	Value int
+}
+
+// String returns the operation as a v<value> index, e.g. "Add(v3)".
+func (o Op) String() string {
+	switch o.Kind {
+	case OpAdd:
+		return fmt.Sprintf("Add(v%d)", o.Value)
+	case OpContains:
+		return fmt.Sprintf("Contains(v%d)", o.Value)
+	}
+	return fmt.Sprintf("Remove(v%d)", o.Value)
+}
+
+// Mismatch is the error returned by Check when a set behaves differently from a built-in map.
+type Mismatch struct {
+	// Ops is the sequence of operations, ending with the first one whose outcome differed
+	Ops []Op
+	// Reason describes the difference
+	Reason string
+}
+
+func (m *Mismatch) Error() string {
+	ops := make([]string, len(m.Ops))
+	for i, o := range m.Ops {
+		ops[i] = o.String()
+	}
+	return fmt.Sprintf("%s after %d operations:\n\t%s", m.Reason, len(m.Ops), strings.Join(ops, "\n\t"))
+}
+
+// Check applies the operations to a new set and to a built-in map, returning a
+// *Mismatch as soon as their contents or the results of Contains differ.
+// A panic of the set is reported as a mismatch too.
+func Check[T any](newSet func() set.Interface[T], value func(i int) T, ops []Op) (err error) {
+	s := newSet()
+	model := make(map[int]bool)
+	done := 0
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = &Mismatch{Ops: ops[:min(done+1, len(ops))], Reason: fmt.Sprintf("panic: %v", r)}
+		}
+	}()
+
+	for i, o := range ops {
+		done = i
+
+		switch o.Kind {
+		case OpAdd:
+			s.Add(value(o.Value))
+			model[o.Value] = true
+		case OpRemove:
+			s.Remove(value(o.Value))
+			delete(model, o.Value)
+		case OpContains:
+			if ok := s.Contains(value(o.Value)); ok != model[o.Value] {
+				reason := fmt.Sprintf("Contains(v%d) = %t; expected %t", o.Value, ok, model[o.Value])
+				return &Mismatch{Ops: ops[:i+1], Reason: reason}
+			}
+		}
+
+		if s.Len() != len(model) {
+			return &Mismatch{Ops: ops[:i+1], Reason: fmt.Sprintf("Len() = %d; expected %d", s.Len(), len(model))}
+		}
+	}
+
+	if reason := compareElements(s, model, value); reason != "" {
+		return &Mismatch{Ops: ops, Reason: reason}
+	}
+
+	return nil
+}
+
+// compareElements returns why the values yielded by Elements differ from the model, or "" if they do not.
+func compareElements[T any](s set.Interface[T], model map[int]bool, value func(i int) T) string {
+	seen := make(map[int]bool)
+
+	for v := range s.Elements() {
+		// Find the index of the value among the values of the model
+		index := -1
+		for i := range model {
+			if utils.Equaler(value(i), v) {
+				index = i
+				break
+			}
+		}
+
+		switch {
+		case index < 0:
+			return fmt.Sprintf("Elements() yielded unexpected value %v", v)
+		case seen[index]:
+			return fmt.Sprintf("Elements() yielded v%d twice", index)
+		}
+		seen[index] = true
+	}
+
+	if len(seen) != len(model) {
+		return fmt.Sprintf("Elements() yielded %d values; expected %d", len(seen), len(model))
+	}
+
+	return ""
+}
+
+// RandomOps returns n random operations over values with indices lower than values.
+// Removals are rarer than additions, so that the set grows.
+func RandomOps(rng *rand.Rand, n, values int) []Op {
+	ops := make([]Op, n)
+
+	for i := range ops {
+		o := Op{Value: rng.Intn(values)}
+
+		switch r := rng.Intn(10); {
+		case r < 5:
+			o.Kind = OpAdd
+		case r < 8:
+			o.Kind = OpContains
+		default:
+			o.Kind = OpRemove
+		}
+
+		ops[i] = o
+	}
+
+	return ops
+}
+
+// Minimize returns the shortest subsequence of ops it finds that still makes Check fail.
+func Minimize[T any](newSet func() set.Interface[T], value func(i int) T, ops []Op) []Op {
+	return shrink.Minimize(ops, func(ops []Op) bool {
+		return Check(newSet, value, ops) != nil
+	})
+}
@@ -0,0 +1,142 @@
+// Package settest provides a conformance test suite for implementations of set.Interface.
+//
+// A wrapper or an alternative implementation can check that it behaves like set.Set:
+//
+//	func TestMySet(t *testing.T) {
+//		settest.Run(t, func() set.Interface[int] { return NewMySet[int]() }, settest.Ints)
+//	}
+package settest
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"math/rand"
+	"testing"
+)
+
+// Ints returns i, to run the suite on sets of int.
+func Ints(i int) int { return i }
+
+// Run runs the conformance suite against the sets returned by newSet.
+//
+// Besides checking each method, it replays random sequences of operations on the set and
+// on a built-in map, with few values to exercise repeated additions and removals, and with
+// many values to cross resize boundaries. On a mismatch it reports the seed of the sequence
+// and a minimal sequence of operations reproducing it.
+//
+// newSet must return a new empty set on every call. value must return distinct
+// values for distinct non-negative i.
+func Run[T any](t *testing.T, newSet func() set.Interface[T], value func(i int) T) {
+	t.Helper()
+
+	t.Run("Empty", func(t *testing.T) {
+		s := newSet()
+
+		if s.Len() != 0 {
+			t.Errorf("Expected length to be 0, got %d", s.Len())
+		}
+		if s.Contains(value(0)) {
+			t.Errorf("Expected empty set to not contain %v", value(0))
+		}
+		for v := range s.Elements() {
+			t.Errorf("Expected empty set to yield nothing, got %v", v)
+		}
+
+		// Removing from an empty set is a no-op
+		s.Remove(value(0))
+	})
+
+	t.Run("Add", func(t *testing.T) {
+		s := newSet()
+		s.Add(value(1), value(2))
+		s.Add(value(1))
+		s.Add()
+
+		if s.Len() != 2 {
+			t.Errorf("Expected length to be 2, got %d", s.Len())
+		}
+		if !s.Contains(value(1)) || !s.Contains(value(2)) || s.Contains(value(3)) {
+			t.Errorf("Expected set to contain exactly %v and %v", value(1), value(2))
+		}
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		s := newSet()
+		s.Add(value(1), value(2))
+		s.Remove(value(1))
+		s.Remove(value(3))
+
+		if s.Len() != 1 || s.Contains(value(1)) || !s.Contains(value(2)) {
+			t.Errorf("Expected set to contain exactly %v", value(2))
+		}
+
+		s.Remove(value(2))
+
+		if s.Len() != 0 {
+			t.Errorf("Expected length to be 0, got %d", s.Len())
+		}
+	})
+
+	t.Run("Elements", func(t *testing.T) {
+		s := newSet()
+		for i := 0; i < 100; i++ {
+			s.Add(value(i))
+		}
+
+		count := 0
+		for v := range s.Elements() {
+			if !s.Contains(v) {
+				t.Errorf("Elements yielded %v, which the set does not contain", v)
+			}
+			count++
+		}
+
+		if count != 100 || s.Len() != 100 {
+			t.Errorf("Expected 100 values, got %d yielded and length %d", count, s.Len())
+		}
+		if !set.Equal(s, s) {
+			t.Errorf("Expected set to be equal to itself")
+		}
+	})
+
+	t.Run("Elements_Break", func(t *testing.T) {
+		s := newSet()
+		s.Add(value(1), value(2), value(3))
+
+		count := 0
+		for range s.Elements() {
+			count++
+			break
+		}
+
+		if count != 1 {
+			t.Errorf("Expected iteration to stop after break, got %d values", count)
+		}
+	})
+
+	t.Run("Differential", func(t *testing.T) {
+		for _, values := range []int{4, 32, 512} {
+			for seed := int64(0); seed < 20; seed++ {
+				ops := RandomOps(rand.New(rand.NewSource(seed)), 400, values)
+
+				if err := Check(newSet, value, ops); err != nil {
+					t.Fatalf("sequence with %d values and seed %d: %v", values, seed, Check(newSet, value, Minimize(newSet, value, ops)))
+				}
+			}
+		}
+	})
+
+	t.Run("Resize", func(t *testing.T) {
+		// Grow the set one value at a time, then shrink it, checking it at each step
+		var ops []Op
+		for i := 0; i < 300; i++ {
+			ops = append(ops, Op{Kind: OpAdd, Value: i}, Op{Kind: OpContains, Value: i}, Op{Kind: OpContains, Value: i + 1})
+		}
+		for i := 0; i < 300; i++ {
+			ops = append(ops, Op{Kind: OpRemove, Value: i}, Op{Kind: OpContains, Value: i}, Op{Kind: OpContains, Value: 299 - i})
+		}
+
+		if err := Check(newSet, value, ops); err != nil {
+			t.Fatal(Check(newSet, value, Minimize(newSet, value, ops)))
+		}
+	})
+}
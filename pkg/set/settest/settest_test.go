@@ -0,0 +1,82 @@
+package settest_test
+
+import (
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"github.com/pietroagazzi/gohashlib/pkg/set/settest"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	settest.Run(t, func() set.Interface[int] { return set.NewSet[int](0, 0.75) }, settest.Ints)
+}
+
+func TestSet_Struct(t *testing.T) {
+	type point struct{ X, Y string }
+
+	settest.Run(t, func() set.Interface[point] { return set.NewSet[point](2, 0.75) }, func(i int) point {
+		return point{strconv.Itoa(i), strconv.Itoa(-i)}
+	})
+}
+
+func TestBitSet(t *testing.T) {
+	settest.Run(t, func() set.Interface[int] { return set.NewBitSet[int](0) }, settest.Ints)
+}
+
+func TestRoaring(t *testing.T) {
+	settest.Run(t, func() set.Interface[uint32] { return set.NewRoaring() }, func(i int) uint32 {
+		// Spread the values across chunks
+		return uint32(i) * 40503
+	})
+}
+
+// stickySet is a Set whose values cannot be removed once it holds more than three.
+type stickySet struct {
+	*set.Set[int]
+}
+
+func (s stickySet) Remove(value int) {
+	if s.Len() <= 3 {
+		s.Set.Remove(value)
+	}
+}
+
+func newStickySet() set.Interface[int] {
+	return stickySet{set.NewSet[int](2, 0.75)}
+}
+
+func TestCheck_Mismatch(t *testing.T) {
+	ops := settest.RandomOps(rand.New(rand.NewSource(1)), 200, 16)
+
+	err := settest.Check(newStickySet, settest.Ints, ops)
+
+	var mismatch *settest.Mismatch
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("Expected a mismatch, got %v", err)
+	}
+
+	minimal := settest.Minimize(newStickySet, settest.Ints, ops)
+
+	// Four distinct values are needed before a removal is ignored
+	if len(minimal) != 5 {
+		t.Errorf("Expected a minimal sequence of 5 operations, got %v", minimal)
+	}
+}
+
+func TestCheck_Panic(t *testing.T) {
+	ops := []settest.Op{{Kind: settest.OpAdd, Value: -1}}
+	newBitSet := func() set.Interface[int] { return set.NewBitSet[int](0) }
+
+	// BitSet panics on negative values
+	if err := settest.Check(newBitSet, settest.Ints, ops); err == nil {
+		t.Errorf("Expected a panic to be reported as a mismatch")
+	}
+}
+
+func TestOp_String(t *testing.T) {
+	if op := (settest.Op{Kind: settest.OpRemove, Value: 2}); op.String() != "Remove(v2)" {
+		t.Errorf("Expected Remove(v2), got %s", op)
+	}
+}
@@ -0,0 +1,59 @@
+package set
+
+// intersectionLen returns the number of elements the set has in common with another set.
+func (s *Set[T]) intersectionLen(other *Set[T]) int {
+	// Iterate over the smaller set
+	if s.Len() > other.Len() {
+		s, other = other, s
+	}
+
+	n := 0
+	for value := range s.Elements() {
+		if other.Contains(value) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Jaccard returns the Jaccard index of the set and another set,
+// the size of their intersection divided by the size of their union.
+// https://en.wikipedia.org/wiki/Jaccard_index
+//
+// Two empty sets have an index of 1.
+func (s *Set[T]) Jaccard(other *Set[T]) float64 {
+	if s.Len() == 0 && other.Len() == 0 {
+		return 1
+	}
+
+	n := s.intersectionLen(other)
+	return float64(n) / float64(s.Len()+other.Len()-n)
+}
+
+// Dice returns the Sørensen–Dice coefficient of the set and another set,
+// twice the size of their intersection divided by the sum of their sizes.
+// https://en.wikipedia.org/wiki/S%C3%B8rensen%E2%80%93Dice_coefficient
+//
+// Two empty sets have a coefficient of 1.
+func (s *Set[T]) Dice(other *Set[T]) float64 {
+	if s.Len() == 0 && other.Len() == 0 {
+		return 1
+	}
+
+	return 2 * float64(s.intersectionLen(other)) / float64(s.Len()+other.Len())
+}
+
+// Overlap returns the overlap coefficient of the set and another set,
+// the size of their intersection divided by the size of the smaller set.
+// https://en.wikipedia.org/wiki/Overlap_coefficient
+//
+// The empty set is a subset of any set, so its coefficient with any set is 1.
+func (s *Set[T]) Overlap(other *Set[T]) float64 {
+	smaller := min(s.Len(), other.Len())
+	if smaller == 0 {
+		return 1
+	}
+
+	return float64(s.intersectionLen(other)) / float64(smaller)
+}
@@ -0,0 +1,41 @@
+package set_test
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"testing"
+)
+
+func TestSet_Similarity(t *testing.T) {
+	s1 := set.NewSet[int](2, 1)
+	s1.Add(1, 2, 3, 4)
+
+	s2 := set.NewSet[int](2, 1)
+	s2.Add(3, 4, 5, 6, 7, 8)
+
+	// The intersection has 2 elements and the union 8
+	if j := s1.Jaccard(s2); j != 0.25 {
+		t.Errorf("Expected Jaccard 0.25, got %v", j)
+	}
+	if d := s1.Dice(s2); d != 0.4 {
+		t.Errorf("Expected Dice 0.4, got %v", d)
+	}
+	if o := s2.Overlap(s1); o != 0.5 {
+		t.Errorf("Expected Overlap 0.5, got %v", o)
+	}
+}
+
+func TestSet_Similarity_Empty(t *testing.T) {
+	empty := set.NewSet[int](2, 1)
+	s := set.NewSet[int](2, 1)
+	s.Add(1)
+
+	if empty.Jaccard(set.NewSet[int](2, 1)) != 1 || empty.Dice(set.NewSet[int](2, 1)) != 1 {
+		t.Errorf("Expected empty sets to be identical")
+	}
+	if empty.Jaccard(s) != 0 || empty.Dice(s) != 0 {
+		t.Errorf("Expected empty set to have nothing in common with %v", s)
+	}
+	if empty.Overlap(s) != 1 {
+		t.Errorf("Expected Overlap 1 for the empty set")
+	}
+}
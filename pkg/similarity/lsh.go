@@ -0,0 +1,160 @@
+package similarity
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"github.com/pietroagazzi/gohashlib/pkg/utils"
+	"math"
+	"slices"
+)
+
+// LSH represents a locality-sensitive hashing index of MinHash signatures,
+// finding the sets similar to a query without comparing it to every set.
+// https://en.wikipedia.org/wiki/Locality-sensitive_hashing
+//
+// Signatures are split into bands of rows, and sets whose signatures are equal on at
+// least one band are candidates. Two sets with a Jaccard index s are candidates with
+// probability 1 - (1 - s^rows)^bands, which rises sharply around (1 / bands)^(1 / rows).
+//
+// Candidates should be checked with Signature.Jaccard or the exact Set.Jaccard,
+// as the index returns false positives and misses some similar sets.
+type LSH[K any] struct {
+	bands int
+	rows  int
+	// buckets maps, for each band, the hash of the band to the keys of the sets sharing it
+	buckets []*hashmap.Map[uint64, *set.Set[K]]
+	// signatures maps the key of each indexed set to its signature
+	signatures *hashmap.Map[K, Signature]
+}
+
+// NewLSH returns a new LSH index of signatures of bands * rows hash functions.
+func NewLSH[K any](bands, rows int) *LSH[K] {
+	bands, rows = max(bands, 1), max(rows, 1)
+
+	buckets := make([]*hashmap.Map[uint64, *set.Set[K]], bands)
+	for i := range buckets {
+		buckets[i] = hashmap.NewMap[uint64, *set.Set[K]](8, hashmap.DefaultThreshold)
+	}
+
+	return &LSH[K]{
+		bands:      bands,
+		rows:       rows,
+		buckets:    buckets,
+		signatures: hashmap.NewMap[K, Signature](8, hashmap.DefaultThreshold),
+	}
+}
+
+// OptimalBands returns the number of bands and rows splitting signatures of n hash functions,
+// such that sets are likely candidates when their Jaccard index is above the threshold.
+//
+// It picks the divisor of n whose threshold (1 / bands)^(1 / rows) is closest.
+func OptimalBands(n int, threshold float64) (bands, rows int) {
+	n = max(n, 1)
+	bands, rows = n, 1
+	best := math.Inf(1)
+
+	for r := 1; r <= n; r++ {
+		if n%r != 0 {
+			continue
+		}
+
+		b := n / r
+		if d := math.Abs(math.Pow(1/float64(b), 1/float64(r)) - threshold); d < best {
+			bands, rows, best = b, r, d
+		}
+	}
+
+	return bands, rows
+}
+
+// Bands returns the number of bands.
+func (l *LSH[K]) Bands() int { return l.bands }
+
+// Rows returns the number of rows in each band.
+func (l *LSH[K]) Rows() int { return l.rows }
+
+// Len returns the number of indexed sets.
+func (l *LSH[K]) Len() int { return l.signatures.Len() }
+
+// bandHash returns the hash of a band of a signature.
+func (l *LSH[K]) bandHash(sig Signature, band int) uint64 {
+	h := uint64(0)
+	for _, v := range sig[band*l.rows : (band+1)*l.rows] {
+		h = utils.Mix64(h ^ v)
+	}
+	return h
+}
+
+// Insert adds the signature of a set to the index, replacing the signature
+// previously indexed under the same key.
+// The signature must have bands * rows values.
+func (l *LSH[K]) Insert(key K, sig Signature) error {
+	if len(sig) != l.bands*l.rows {
+		return ErrIncompatible
+	}
+
+	l.Remove(key)
+
+	for band, buckets := range l.buckets {
+		h := l.bandHash(sig, band)
+
+		bucket, ok := buckets.Get(h)
+		if !ok {
+			bucket = set.NewSet[K](2, hashmap.DefaultThreshold)
+			buckets.Set(h, bucket)
+		}
+		bucket.Add(key)
+	}
+
+	// A copy keeps the bands of the key if the caller changes the signature
+	l.signatures.Set(key, slices.Clone(sig))
+
+	return nil
+}
+
+// Remove removes the set indexed under a key.
+func (l *LSH[K]) Remove(key K) {
+	sig, ok := l.signatures.Get(key)
+	if !ok {
+		return
+	}
+
+	for band, buckets := range l.buckets {
+		h := l.bandHash(sig, band)
+
+		bucket, ok := buckets.Get(h)
+		if !ok {
+			continue
+		}
+		bucket.Remove(key)
+		if bucket.Len() == 0 {
+			buckets.Delete(h)
+		}
+	}
+
+	l.signatures.Delete(key)
+}
+
+// Signature returns a copy of the signature indexed under a key.
+func (l *LSH[K]) Signature(key K) (Signature, bool) {
+	sig, ok := l.signatures.Get(key)
+	return slices.Clone(sig), ok
+}
+
+// Query returns the keys of the indexed sets sharing at least one band with a signature.
+// The signature must have bands * rows values.
+func (l *LSH[K]) Query(sig Signature) (*set.Set[K], error) {
+	if len(sig) != l.bands*l.rows {
+		return nil, ErrIncompatible
+	}
+
+	result := set.NewSet[K](8, hashmap.DefaultThreshold)
+
+	for band, buckets := range l.buckets {
+		if bucket, ok := buckets.Get(l.bandHash(sig, band)); ok {
+			result.UnionWith(bucket)
+		}
+	}
+
+	return result, nil
+}
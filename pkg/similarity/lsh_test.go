@@ -0,0 +1,111 @@
+package similarity_test
+
+import (
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/similarity"
+	"slices"
+	"testing"
+)
+
+func TestOptimalBands(t *testing.T) {
+	bands, rows := similarity.OptimalBands(128, 0.5)
+
+	// (1/16)^(1/8) is about 0.71 and (1/32)^(1/4) about 0.42
+	if bands*rows != 128 || bands != 32 || rows != 4 {
+		t.Errorf("Expected 32 bands of 4 rows, got %d of %d", bands, rows)
+	}
+}
+
+func TestLSH_Query(t *testing.T) {
+	bands, rows := similarity.OptimalBands(128, 0.5)
+	mh := similarity.NewMinHasher[int](128, 7)
+	index := similarity.NewLSH[string](bands, rows)
+
+	documents := map[string][2]int{
+		"a": {0, 1000},
+		// Jaccard index 0.82 with a
+		"b": {100, 1100},
+		// Jaccard index 0.33 with a
+		"c": {500, 1500},
+		"d": {5000, 6000},
+	}
+
+	for key, r := range documents {
+		sig, _ := mh.Signature(rangeSet(r[0], r[1]))
+		if err := index.Insert(key, sig); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if index.Len() != 4 {
+		t.Errorf("Expected 4 indexed sets, got %d", index.Len())
+	}
+
+	query, _ := mh.Signature(rangeSet(0, 1000))
+	candidates, err := index.Query(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !candidates.Contains("a") || !candidates.Contains("b") {
+		t.Errorf("Expected a and b to be candidates, got %v", candidates)
+	}
+	if candidates.Contains("d") {
+		t.Errorf("Expected d not to be a candidate, got %v", candidates)
+	}
+
+	index.Remove("b")
+	candidates, _ = index.Query(query)
+
+	if candidates.Contains("b") || index.Len() != 3 {
+		t.Errorf("Expected b to be removed, got %v", candidates)
+	}
+	if _, ok := index.Signature("b"); ok {
+		t.Errorf("Expected no signature for b")
+	}
+}
+
+func TestLSH_Insert_Replace(t *testing.T) {
+	mh := similarity.NewMinHasher[int](16, 7)
+	index := similarity.NewLSH[string](4, 4)
+
+	old, _ := mh.Signature(rangeSet(0, 100))
+	_ = index.Insert("a", old)
+
+	replacement, _ := mh.Signature(rangeSet(1000, 1100))
+	_ = index.Insert("a", replacement)
+
+	if candidates, _ := index.Query(old); candidates.Contains("a") {
+		t.Errorf("Expected the old signature of a to be replaced")
+	}
+	if candidates, _ := index.Query(replacement); !candidates.Contains("a") {
+		t.Errorf("Expected a to be a candidate of its signature")
+	}
+
+	if err := index.Insert("b", old[:8]); !errors.Is(err, similarity.ErrIncompatible) {
+		t.Errorf("Expected ErrIncompatible, got %v", err)
+	}
+}
+
+func TestLSH_Insert_Copy(t *testing.T) {
+	mh := similarity.NewMinHasher[int](16, 7)
+	index := similarity.NewLSH[string](4, 4)
+
+	sig, _ := mh.Signature(rangeSet(0, 100))
+	original := slices.Clone(sig)
+	_ = index.Insert("a", sig)
+
+	// Changing the signature after inserting it does not change the index
+	for i := range sig {
+		sig[i]++
+	}
+
+	if candidates, _ := index.Query(original); !candidates.Contains("a") {
+		t.Errorf("Expected a to be a candidate of its original signature")
+	}
+
+	index.Remove("a")
+	if candidates, _ := index.Query(original); candidates.Len() != 0 {
+		t.Errorf("Expected no candidates, got %v", candidates)
+	}
+}
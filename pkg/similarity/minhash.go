@@ -0,0 +1,83 @@
+// Package similarity estimates the similarity of sets without comparing them element by element.
+package similarity
+
+import (
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"github.com/pietroagazzi/gohashlib/pkg/utils"
+	"math"
+)
+
+// ErrIncompatible is returned when comparing signatures of different lengths,
+// or indexing signatures of the wrong length.
+var ErrIncompatible = errors.New("similarity: incompatible signatures")
+
+// Signature is the MinHash signature of a set: for each hash function,
+// the smallest hash of its elements.
+type Signature []uint64
+
+// Jaccard returns the estimated Jaccard index of the sets of two signatures,
+// the fraction of hash functions whose smallest hashes are equal.
+// Both signatures must come from the same MinHasher.
+func (sig Signature) Jaccard(other Signature) (float64, error) {
+	if len(sig) != len(other) || len(sig) == 0 {
+		return 0, ErrIncompatible
+	}
+
+	equal := 0
+	for i := range sig {
+		if sig[i] == other[i] {
+			equal++
+		}
+	}
+
+	return float64(equal) / float64(len(sig)), nil
+}
+
+// MinHasher computes MinHash signatures, estimating the Jaccard index of two sets
+// with an error of about 1 / sqrt(n) for n hash functions.
+// https://en.wikipedia.org/wiki/MinHash
+//
+// Elements are hashed once from the encoding of hashmap.Map keys, and the hash functions
+// are derived from that hash by mixing it with a different seed for each function.
+type MinHasher[T any] struct {
+	seeds []uint64
+}
+
+// NewMinHasher returns a new MinHasher with n hash functions.
+// Signatures can only be compared if they come from MinHashers with the same n and seed.
+func NewMinHasher[T any](n int, seed uint64) *MinHasher[T] {
+	seeds := make([]uint64, max(n, 1))
+	for i := range seeds {
+		// Consecutive values of the SplitMix64 sequence
+		seed += 0x9e3779b97f4a7c15
+		seeds[i] = utils.Mix64(seed)
+	}
+
+	return &MinHasher[T]{seeds: seeds}
+}
+
+// Len returns the number of hash functions, the length of the signatures.
+func (mh *MinHasher[T]) Len() int { return len(mh.seeds) }
+
+// Signature returns the MinHash signature of a set.
+// It returns an error if an element cannot be encoded.
+func (mh *MinHasher[T]) Signature(s *set.Set[T]) (Signature, error) {
+	sig := make(Signature, len(mh.seeds))
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+
+	for value := range s.Elements() {
+		h, err := utils.Hash64(value)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, seed := range mh.seeds {
+			sig[i] = min(sig[i], utils.Mix64(h^seed))
+		}
+	}
+
+	return sig, nil
+}
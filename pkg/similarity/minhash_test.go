@@ -0,0 +1,66 @@
+package similarity_test
+
+import (
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"github.com/pietroagazzi/gohashlib/pkg/set"
+	"github.com/pietroagazzi/gohashlib/pkg/similarity"
+	"math"
+	"testing"
+)
+
+// rangeSet returns the set of the integers in [from, to).
+func rangeSet(from, to int) *set.Set[int] {
+	s := set.NewSet[int](8, hashmap.DefaultThreshold)
+	for i := from; i < to; i++ {
+		s.Add(i)
+	}
+	return s
+}
+
+func TestMinHasher_Signature(t *testing.T) {
+	mh := similarity.NewMinHasher[int](256, 42)
+
+	tests := []struct {
+		a, b *set.Set[int]
+	}{
+		{rangeSet(0, 1000), rangeSet(0, 1000)},
+		{rangeSet(0, 1000), rangeSet(500, 1500)},
+		{rangeSet(0, 1000), rangeSet(900, 1900)},
+		{rangeSet(0, 1000), rangeSet(1000, 2000)},
+	}
+
+	for _, test := range tests {
+		sa, _ := mh.Signature(test.a)
+		sb, _ := mh.Signature(test.b)
+
+		estimate, err := sa.Jaccard(sb)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// 1 / sqrt(256) is about 0.06, allow three standard errors
+		if exact := test.a.Jaccard(test.b); math.Abs(estimate-exact) > 0.2 {
+			t.Errorf("Expected estimate close to %v, got %v", exact, estimate)
+		}
+	}
+}
+
+func TestMinHasher_Seed(t *testing.T) {
+	s := rangeSet(0, 100)
+
+	s1, _ := similarity.NewMinHasher[int](16, 1).Signature(s)
+	s2, _ := similarity.NewMinHasher[int](16, 1).Signature(s)
+	s3, _ := similarity.NewMinHasher[int](16, 2).Signature(s)
+
+	if j, _ := s1.Jaccard(s2); j != 1 {
+		t.Errorf("Expected MinHashers with the same seed to agree, got %v", j)
+	}
+	if j, _ := s1.Jaccard(s3); j == 1 {
+		t.Errorf("Expected MinHashers with different seeds to differ")
+	}
+
+	if _, err := s1.Jaccard(s1[:8]); !errors.Is(err, similarity.ErrIncompatible) {
+		t.Errorf("Expected ErrIncompatible, got %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package sketch
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/utils"
+	"math"
+)
+
+// CountMinSketch represents an estimator of the number of times each value occurs in a stream,
+// using a fixed amount of memory.
+// https://en.wikipedia.org/wiki/Count%E2%80%93min_sketch
+//
+// The sketch is a grid of counters with depth rows of width columns. A value increments one
+// counter per row, picked by double hashing the encoding of hashmap.Map keys, and its count is
+// estimated by the smallest of them. Estimates never undercount, and overcount by at most
+// epsilon times the total count with probability 1 - delta.
+//
+// Counters are incremented with conservative update: only the counters below the new estimate
+// are raised, which lowers the overcount without affecting the guarantees.
+type CountMinSketch[T any] struct {
+	// counters holds the rows one after the other
+	counters []uint64
+	width    uint64
+	depth    uint64
+	// total is the sum of the counts added to the sketch
+	total uint64
+}
+
+// NewCountMinSketch returns a new CountMinSketch whose estimates overcount by at most
+// epsilon times the total count, with probability 1 - delta.
+//
+// width = ⌈e / epsilon⌉ and depth = ⌈ln(1 / delta)⌉
+func NewCountMinSketch[T any](epsilon, delta float64) *CountMinSketch[T] {
+	epsilon = max(epsilon, math.SmallestNonzeroFloat64)
+	delta = min(max(delta, math.SmallestNonzeroFloat64), 1)
+
+	width := uint64(math.Ceil(math.E / epsilon))
+	depth := uint64(math.Ceil(math.Log(1 / delta)))
+
+	return NewCountMinSketchWithParameters[T](width, depth)
+}
+
+// NewCountMinSketchWithParameters returns a new CountMinSketch with depth rows of width counters.
+func NewCountMinSketchWithParameters[T any](width, depth uint64) *CountMinSketch[T] {
+	width, depth = max(width, 1), max(depth, 1)
+
+	return &CountMinSketch[T]{
+		counters: make([]uint64, width*depth),
+		width:    width,
+		depth:    depth,
+	}
+}
+
+// Width returns the number of counters in each row.
+func (s *CountMinSketch[T]) Width() uint64 { return s.width }
+
+// Depth returns the number of rows.
+func (s *CountMinSketch[T]) Depth() uint64 { return s.depth }
+
+// Total returns the sum of the counts added to the sketch.
+func (s *CountMinSketch[T]) Total() uint64 { return s.total }
+
+// cell returns the position in counters of the counter of a value in a row.
+func (s *CountMinSketch[T]) cell(h1, h2, row uint64) uint64 {
+	return row*s.width + (h1+row*h2)%s.width
+}
+
+// Add adds count occurrences of a value to the sketch.
+// It returns an error if the value cannot be encoded.
+func (s *CountMinSketch[T]) Add(value T, count uint64) error {
+	_, err := s.add(value, count)
+	return err
+}
+
+// add adds count occurrences of a value to the sketch, and returns its new estimate.
+func (s *CountMinSketch[T]) add(value T, count uint64) (uint64, error) {
+	h1, h2, err := utils.DoubleHash(value)
+	if err != nil {
+		return 0, err
+	}
+
+	estimate := s.estimate(h1, h2) + count
+
+	for row := uint64(0); row < s.depth; row++ {
+		i := s.cell(h1, h2, row)
+		s.counters[i] = max(s.counters[i], estimate)
+	}
+	s.total += count
+
+	return estimate, nil
+}
+
+// Count returns the estimated number of occurrences of a value.
+// It returns an error if the value cannot be encoded.
+func (s *CountMinSketch[T]) Count(value T) (uint64, error) {
+	h1, h2, err := utils.DoubleHash(value)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.estimate(h1, h2), nil
+}
+
+// estimate returns the smallest counter of a value.
+func (s *CountMinSketch[T]) estimate(h1, h2 uint64) uint64 {
+	estimate := uint64(math.MaxUint64)
+	for row := uint64(0); row < s.depth; row++ {
+		estimate = min(estimate, s.counters[s.cell(h1, h2, row)])
+	}
+	return estimate
+}
+
+// Merge adds to the sketch all the occurrences added to another sketch.
+// Both sketches must have the same width and depth.
+//
+// Counters are summed, so estimates keep their guarantees, but the merged sketch
+// overcounts as much as one built without conservative update.
+func (s *CountMinSketch[T]) Merge(other *CountMinSketch[T]) error {
+	if s.width != other.width || s.depth != other.depth {
+		return ErrIncompatible
+	}
+
+	for i, c := range other.counters {
+		s.counters[i] += c
+	}
+	s.total += other.total
+
+	return nil
+}
+
+// Clear removes all occurrences from the sketch.
+func (s *CountMinSketch[T]) Clear() {
+	clear(s.counters)
+	s.total = 0
+}
@@ -0,0 +1,91 @@
+package sketch_test
+
+import (
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/sketch"
+	"math/rand"
+	"testing"
+)
+
+func TestNewCountMinSketch(t *testing.T) {
+	s := sketch.NewCountMinSketch[int](0.001, 0.01)
+
+	// ⌈e / 0.001⌉ and ⌈ln(100)⌉
+	if s.Width() != 2719 || s.Depth() != 5 {
+		t.Errorf("Expected 5 rows of 2719 counters, got %d of %d", s.Depth(), s.Width())
+	}
+}
+
+func TestCountMinSketch_Count(t *testing.T) {
+	epsilon := 0.001
+	s := sketch.NewCountMinSketch[event](epsilon, 0.01)
+	exact := make(map[event]uint64)
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20000; i++ {
+		e := event{rng.Intn(2000), "view"}
+		n := uint64(rng.Intn(3) + 1)
+
+		if err := s.Add(e, n); err != nil {
+			t.Fatal(err)
+		}
+		exact[e] += n
+	}
+
+	bound := uint64(epsilon * float64(s.Total()))
+	for e, n := range exact {
+		count, _ := s.Count(e)
+		if count < n || count > n+bound {
+			t.Errorf("Expected count of %v between %d and %d, got %d", e, n, n+bound, count)
+		}
+	}
+
+	if count, _ := s.Count(event{-1, "view"}); count > bound {
+		t.Errorf("Expected count of an absent value at most %d, got %d", bound, count)
+	}
+}
+
+func TestCountMinSketch_Merge(t *testing.T) {
+	s1 := sketch.NewCountMinSketch[string](0.01, 0.01)
+	s2 := sketch.NewCountMinSketch[string](0.01, 0.01)
+
+	_ = s1.Add("a", 3)
+	_ = s2.Add("a", 4)
+	_ = s2.Add("b", 1)
+
+	if err := s1.Merge(s2); err != nil {
+		t.Fatal(err)
+	}
+
+	if count, _ := s1.Count("a"); count != 7 {
+		t.Errorf("Expected count 7, got %d", count)
+	}
+	if s1.Total() != 8 {
+		t.Errorf("Expected total 8, got %d", s1.Total())
+	}
+
+	if err := s1.Merge(sketch.NewCountMinSketch[string](0.1, 0.01)); !errors.Is(err, sketch.ErrIncompatible) {
+		t.Errorf("Expected ErrIncompatible, got %v", err)
+	}
+
+	s1.Clear()
+
+	if count, _ := s1.Count("a"); count != 0 || s1.Total() != 0 {
+		t.Errorf("Expected empty sketch after Clear, got count %d and total %d", count, s1.Total())
+	}
+}
+
+func TestCountMinSketch_ConservativeUpdate(t *testing.T) {
+	// A single counter per row makes every value collide
+	s := sketch.NewCountMinSketchWithParameters[string](1, 1)
+
+	_ = s.Add("a", 5)
+	_ = s.Add("b", 2)
+
+	// Without conservative update the counter would be 7 + 2
+	_ = s.Add("b", 2)
+
+	if count, _ := s.Count("b"); count != 9 {
+		t.Errorf("Expected count 9, got %d", count)
+	}
+}
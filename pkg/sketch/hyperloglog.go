@@ -0,0 +1,287 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/utils"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// ErrIncompatible is returned when merging sketches built with different parameters.
+var ErrIncompatible = errors.New("sketch: incompatible parameters")
+
+// ErrInvalidEncoding is returned when decoding bytes that are not an encoded sketch.
+var ErrInvalidEncoding = errors.New("sketch: invalid encoding")
+
+// ErrPrecision is returned when creating a HyperLogLog with an unsupported precision.
+var ErrPrecision = errors.New("sketch: precision must be between 4 and 18")
+
+const (
+	MinPrecision = 4
+	MaxPrecision = 18
+)
+
+// HyperLogLog represents an estimator of the number of distinct values in a stream,
+// using a fixed amount of memory.
+// https://en.wikipedia.org/wiki/HyperLogLog
+//
+// Each value is hashed to 64 bits with the pipeline of hashmap.Map: the first p bits pick
+// one of 2^p registers, which keeps the longest run of leading zeros seen in the other bits.
+// The relative error of Count is about 1.04 / sqrt(2^p).
+//
+// While few registers are set, they are kept in a sorted sparse list instead of an array,
+// so small sketches stay small.
+type HyperLogLog[T any] struct {
+	// p is the precision, the number of bits picking a register
+	p uint8
+	// registers is the dense representation, nil while the sketch is sparse
+	registers []uint8
+	// sparse holds the registers that are set, as index<<8 | value, sorted by index
+	sparse []uint32
+}
+
+// NewHyperLogLog returns a new HyperLogLog with 2^precision registers.
+func NewHyperLogLog[T any](precision uint8) (*HyperLogLog[T], error) {
+	if precision < MinPrecision || precision > MaxPrecision {
+		return nil, ErrPrecision
+	}
+
+	return &HyperLogLog[T]{p: precision}, nil
+}
+
+// Precision returns the precision of the sketch.
+func (h *HyperLogLog[T]) Precision() uint8 { return h.p }
+
+// hash returns the register index and value of a value.
+func (h *HyperLogLog[T]) hash(value T) (index uint32, rho uint8, err error) {
+	x, err := utils.Hash64(value)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// FNV-1a spreads short inputs poorly over the high bits, which pick the register
+	x = utils.Mix64(x)
+
+	index = uint32(x >> (64 - h.p))
+	// The position of the first set bit in the remaining bits, capped at 64 - p + 1
+	rho = uint8(min(bits.LeadingZeros64(x<<h.p), 64-int(h.p)) + 1)
+
+	return index, rho, nil
+}
+
+// Add adds a value to the sketch.
+// It returns an error if the value cannot be encoded.
+func (h *HyperLogLog[T]) Add(value T) error {
+	index, rho, err := h.hash(value)
+	if err != nil {
+		return err
+	}
+
+	h.set(index, rho)
+	return nil
+}
+
+// set raises the register at index to rho.
+func (h *HyperLogLog[T]) set(index uint32, rho uint8) {
+	if h.registers != nil {
+		h.registers[index] = max(h.registers[index], rho)
+		return
+	}
+
+	i, found := slices.BinarySearchFunc(h.sparse, index, func(e, index uint32) int {
+		return int(e>>8) - int(index)
+	})
+
+	if found {
+		h.sparse[i] = index<<8 | uint32(max(uint8(h.sparse[i]), rho))
+		return
+	}
+
+	h.sparse = slices.Insert(h.sparse, i, index<<8|uint32(rho))
+
+	// A sparse entry takes 4 bytes and a register 1, so switch once the list is larger
+	if len(h.sparse) > h.m()/4 {
+		h.densify()
+	}
+}
+
+// densify switches to the dense representation.
+func (h *HyperLogLog[T]) densify() {
+	h.registers = make([]uint8, h.m())
+	for _, e := range h.sparse {
+		h.registers[e>>8] = uint8(e)
+	}
+	h.sparse = nil
+}
+
+// m returns the number of registers.
+func (h *HyperLogLog[T]) m() int { return 1 << h.p }
+
+// Count returns the estimated number of distinct values added to the sketch.
+//
+// It uses the improved estimator of Ertl, which corrects the bias of the original
+// estimator at small and large cardinalities without empirical tables.
+// https://arxiv.org/abs/1702.01284
+func (h *HyperLogLog[T]) Count() uint64 {
+	q := 64 - int(h.p)
+	m := float64(h.m())
+
+	// histogram[k] is the number of registers holding k
+	histogram := make([]float64, q+2)
+	if h.registers != nil {
+		for _, r := range h.registers {
+			histogram[r]++
+		}
+	} else {
+		histogram[0] = m - float64(len(h.sparse))
+		for _, e := range h.sparse {
+			histogram[uint8(e)]++
+		}
+	}
+
+	z := m * tau(1-histogram[q+1]/m)
+	for k := q; k >= 1; k-- {
+		z = 0.5 * (z + histogram[k])
+	}
+	z += m * sigma(histogram[0]/m)
+
+	return uint64(math.Round(m * m / (2 * math.Ln2 * z)))
+}
+
+// sigma is the series correcting the estimate for empty registers.
+func sigma(x float64) float64 {
+	if x == 1 {
+		return math.Inf(1)
+	}
+
+	y, z := 1.0, x
+	for {
+		x *= x
+		next := z + x*y
+		y += y
+		if next == z {
+			return z
+		}
+		z = next
+	}
+}
+
+// tau is the series correcting the estimate for saturated registers.
+func tau(x float64) float64 {
+	if x == 0 || x == 1 {
+		return 0
+	}
+
+	y, z := 1.0, 1-x
+	for {
+		x = math.Sqrt(x)
+		previous := z
+		y *= 0.5
+		z -= (1 - x) * (1 - x) * y
+		if previous == z {
+			return z / 3
+		}
+	}
+}
+
+// Merge adds to the sketch all the values added to another sketch,
+// as if both streams had been added to it.
+// Both sketches must have the same precision.
+func (h *HyperLogLog[T]) Merge(other *HyperLogLog[T]) error {
+	if h.p != other.p {
+		return ErrIncompatible
+	}
+
+	if other.registers == nil {
+		for _, e := range other.sparse {
+			h.set(e>>8, uint8(e))
+		}
+		return nil
+	}
+
+	if h.registers == nil {
+		h.densify()
+	}
+	for i, r := range other.registers {
+		h.registers[i] = max(h.registers[i], r)
+	}
+
+	return nil
+}
+
+// Clear removes all values from the sketch.
+func (h *HyperLogLog[T]) Clear() {
+	h.registers = nil
+	h.sparse = nil
+}
+
+// hllMagic starts every encoded HyperLogLog.
+const hllMagic = "HLL1"
+
+// MarshalBinary encodes the sketch into bytes: a magic string, the precision, and either
+// a zero byte followed by the number of sparse entries and the entries, or a one byte
+// followed by the registers. Integers are little-endian.
+func (h *HyperLogLog[T]) MarshalBinary() ([]byte, error) {
+	out := append([]byte(hllMagic), h.p)
+
+	if h.registers != nil {
+		out = append(out, 1)
+		return append(out, h.registers...), nil
+	}
+
+	out = append(out, 0)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(h.sparse)))
+	for _, e := range h.sparse {
+		out = binary.LittleEndian.AppendUint32(out, e)
+	}
+
+	return out, nil
+}
+
+// UnmarshalBinary decodes bytes produced by MarshalBinary, replacing the content of the sketch.
+func (h *HyperLogLog[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < len(hllMagic)+2 || string(data[:len(hllMagic)]) != hllMagic {
+		return ErrInvalidEncoding
+	}
+
+	p, dense := data[len(hllMagic)], data[len(hllMagic)+1]
+	data = data[len(hllMagic)+2:]
+
+	if p < MinPrecision || p > MaxPrecision {
+		return ErrInvalidEncoding
+	}
+	result := HyperLogLog[T]{p: p}
+	maxRho := uint8(64 - p + 1)
+
+	switch dense {
+	case 1:
+		if len(data) != result.m() {
+			return ErrInvalidEncoding
+		}
+		result.registers = append([]uint8(nil), data...)
+		for _, r := range result.registers {
+			if r > maxRho {
+				return ErrInvalidEncoding
+			}
+		}
+	case 0:
+		if len(data) < 4 || uint64(len(data)) != 4+4*uint64(binary.LittleEndian.Uint32(data)) {
+			return ErrInvalidEncoding
+		}
+		result.sparse = make([]uint32, binary.LittleEndian.Uint32(data))
+		for i := range result.sparse {
+			e := binary.LittleEndian.Uint32(data[4+4*i:])
+			if e>>8 >= uint32(result.m()) || uint8(e) == 0 || uint8(e) > maxRho || i > 0 && e>>8 <= result.sparse[i-1]>>8 {
+				return ErrInvalidEncoding
+			}
+			result.sparse[i] = e
+		}
+	default:
+		return ErrInvalidEncoding
+	}
+
+	*h = result
+	return nil
+}
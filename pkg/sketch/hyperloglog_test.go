@@ -0,0 +1,143 @@
+package sketch_test
+
+import (
+	"errors"
+	"github.com/pietroagazzi/gohashlib/pkg/sketch"
+	"math"
+	"testing"
+)
+
+type event struct {
+	User int
+	Kind string
+}
+
+// relativeError returns the relative error of an estimate.
+func relativeError(estimate uint64, actual int) float64 {
+	return math.Abs(float64(estimate)-float64(actual)) / float64(actual)
+}
+
+func TestNewHyperLogLog(t *testing.T) {
+	for _, p := range []uint8{3, 19} {
+		if _, err := sketch.NewHyperLogLog[int](p); !errors.Is(err, sketch.ErrPrecision) {
+			t.Errorf("Expected ErrPrecision for precision %d, got %v", p, err)
+		}
+	}
+}
+
+func TestHyperLogLog_Count(t *testing.T) {
+	for _, n := range []int{0, 1, 10, 100, 1000, 10000, 100000} {
+		h, _ := sketch.NewHyperLogLog[event](14)
+
+		for i := 0; i < n; i++ {
+			// Every value is added twice, and counted once
+			_ = h.Add(event{i, "click"})
+			_ = h.Add(event{i, "click"})
+		}
+
+		count := h.Count()
+		if n == 0 && count != 0 {
+			t.Errorf("Expected empty sketch to count 0, got %d", count)
+		}
+		// 1.04 / sqrt(2^14) is about 0.8%, allow four standard errors
+		if n > 0 && relativeError(count, n) > 0.035 {
+			t.Errorf("Expected about %d distinct values, got %d", n, count)
+		}
+	}
+}
+
+func TestHyperLogLog_Merge(t *testing.T) {
+	h1, _ := sketch.NewHyperLogLog[int](12)
+	h2, _ := sketch.NewHyperLogLog[int](12)
+	sparse, _ := sketch.NewHyperLogLog[int](12)
+
+	for i := 0; i < 30000; i++ {
+		_ = h1.Add(i)
+	}
+	for i := 20000; i < 50000; i++ {
+		_ = h2.Add(i)
+	}
+	for i := 0; i < 10; i++ {
+		_ = sparse.Add(-i)
+	}
+
+	if err := h1.Merge(h2); err != nil {
+		t.Fatal(err)
+	}
+	if err := h1.Merge(sparse); err != nil {
+		t.Fatal(err)
+	}
+	if count := h1.Count(); relativeError(count, 50010) > 0.07 {
+		t.Errorf("Expected about 50010 distinct values, got %d", count)
+	}
+
+	// Merging a dense sketch into a sparse one
+	if err := sparse.Merge(h2); err != nil {
+		t.Fatal(err)
+	}
+	if count := sparse.Count(); relativeError(count, 30010) > 0.07 {
+		t.Errorf("Expected about 30010 distinct values, got %d", count)
+	}
+
+	other, _ := sketch.NewHyperLogLog[int](10)
+	if err := h1.Merge(other); !errors.Is(err, sketch.ErrIncompatible) {
+		t.Errorf("Expected ErrIncompatible, got %v", err)
+	}
+}
+
+func TestHyperLogLog_Sparse(t *testing.T) {
+	h, _ := sketch.NewHyperLogLog[int](14)
+	for i := 0; i < 100; i++ {
+		_ = h.Add(i)
+	}
+
+	sparse, _ := h.MarshalBinary()
+
+	for i := 100; i < 10000; i++ {
+		_ = h.Add(i)
+	}
+
+	dense, _ := h.MarshalBinary()
+
+	// 100 sparse entries take 4 bytes each, the dense registers 2^14 bytes
+	if len(sparse) > 500 || len(dense) < 1<<14 {
+		t.Errorf("Expected sparse encoding to be small, got %d and %d bytes", len(sparse), len(dense))
+	}
+
+	h.Clear()
+
+	if h.Count() != 0 {
+		t.Errorf("Expected count to be 0 after Clear, got %d", h.Count())
+	}
+}
+
+func TestHyperLogLog_MarshalBinary(t *testing.T) {
+	for _, n := range []int{50, 50000} {
+		h, _ := sketch.NewHyperLogLog[int](12)
+		for i := 0; i < n; i++ {
+			_ = h.Add(i)
+		}
+
+		data, _ := h.MarshalBinary()
+
+		decoded, _ := sketch.NewHyperLogLog[int](4)
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if decoded.Count() != h.Count() || decoded.Precision() != 12 {
+			t.Errorf("Expected decoded sketch to count %d, got %d", h.Count(), decoded.Count())
+		}
+
+		if err := decoded.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, sketch.ErrInvalidEncoding) {
+			t.Errorf("Expected ErrInvalidEncoding, got %v", err)
+		}
+	}
+}
+
+func TestHyperLogLog_Add_Unencodable(t *testing.T) {
+	h, _ := sketch.NewHyperLogLog[any](4)
+
+	if err := h.Add(func() {}); err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+}
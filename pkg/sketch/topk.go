@@ -0,0 +1,137 @@
+package sketch
+
+import (
+	"container/heap"
+	"github.com/pietroagazzi/gohashlib/pkg/hashmap"
+	"sort"
+)
+
+// Item is a value along with its estimated number of occurrences.
+type Item[T any] struct {
+	Value T
+	Count uint64
+}
+
+// TopK represents a tracker of the k most frequent values of a stream, the heavy hitters.
+//
+// Occurrences are counted by a CountMinSketch, while the k values with the highest estimates
+// are kept as candidates in a min-heap, indexed by a hashmap.Map. A value replaces the least
+// frequent candidate once its estimate exceeds it.
+type TopK[T any] struct {
+	k      int
+	sketch *CountMinSketch[T]
+	// heap holds the candidates, the least frequent first
+	heap candidateHeap[T]
+	// candidates maps each candidate value to its position in heap
+	candidates *hashmap.Map[T, *candidate[T]]
+}
+
+// candidate is a value tracked by a TopK.
+type candidate[T any] struct {
+	Item[T]
+	// index is the position of the candidate in the heap
+	index int
+}
+
+// NewTopK returns a new TopK tracking the k most frequent values,
+// counted by a CountMinSketch with the given error bounds.
+func NewTopK[T any](k int, epsilon, delta float64) *TopK[T] {
+	k = max(k, 1)
+
+	return &TopK[T]{
+		k:          k,
+		sketch:     NewCountMinSketch[T](epsilon, delta),
+		heap:       make(candidateHeap[T], 0, k),
+		candidates: hashmap.NewMap[T, *candidate[T]](uint32(k), hashmap.DefaultThreshold),
+	}
+}
+
+// K returns the number of values tracked.
+func (t *TopK[T]) K() int { return t.k }
+
+// Add adds count occurrences of a value.
+// It returns an error if the value cannot be encoded.
+func (t *TopK[T]) Add(value T, count uint64) error {
+	estimate, err := t.sketch.add(value, count)
+	if err != nil {
+		return err
+	}
+
+	if c, ok := t.candidates.Get(value); ok {
+		c.Count = estimate
+		heap.Fix(&t.heap, c.index)
+		return nil
+	}
+
+	if len(t.heap) < t.k {
+		c := &candidate[T]{Item: Item[T]{Value: value, Count: estimate}}
+		heap.Push(&t.heap, c)
+		t.candidates.Set(value, c)
+		return nil
+	}
+
+	// Replace the least frequent candidate, reusing its place in the heap
+	if least := t.heap[0]; estimate > least.Count {
+		t.candidates.Delete(least.Value)
+		least.Item = Item[T]{Value: value, Count: estimate}
+		heap.Fix(&t.heap, 0)
+		t.candidates.Set(value, least)
+	}
+
+	return nil
+}
+
+// Count returns the estimated number of occurrences of a value, tracked or not.
+// It returns an error if the value cannot be encoded.
+func (t *TopK[T]) Count(value T) (uint64, error) {
+	return t.sketch.Count(value)
+}
+
+// Contains reports whether a value is one of the tracked values.
+func (t *TopK[T]) Contains(value T) bool {
+	_, ok := t.candidates.Get(value)
+	return ok
+}
+
+// Items returns the tracked values, from the most to the least frequent.
+func (t *TopK[T]) Items() []Item[T] {
+	result := make([]Item[T], len(t.heap))
+	for i, c := range t.heap {
+		result[i] = c.Item
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Count > result[j].Count })
+
+	return result
+}
+
+// Clear removes all occurrences and tracked values.
+func (t *TopK[T]) Clear() {
+	t.sketch.Clear()
+	t.heap = t.heap[:0]
+	t.candidates.Clear()
+}
+
+// candidateHeap is a min-heap of candidates, ordered by count.
+type candidateHeap[T any] []*candidate[T]
+
+func (h candidateHeap[T]) Len() int           { return len(h) }
+func (h candidateHeap[T]) Less(i, j int) bool { return h[i].Count < h[j].Count }
+
+func (h candidateHeap[T]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+
+func (h *candidateHeap[T]) Push(x any) {
+	c := x.(*candidate[T])
+	c.index = len(*h)
+	*h = append(*h, c)
+}
+
+func (h *candidateHeap[T]) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
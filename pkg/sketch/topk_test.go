@@ -0,0 +1,67 @@
+package sketch_test
+
+import (
+	"github.com/pietroagazzi/gohashlib/pkg/sketch"
+	"math/rand"
+	"testing"
+)
+
+func TestTopK_Items(t *testing.T) {
+	top := sketch.NewTopK[int](5, 0.001, 0.01)
+
+	// Values 0 to 4 are heavy hitters among many rare values
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 50000; i++ {
+		v := 5 + rng.Intn(10000)
+		if i%2 == 0 {
+			v = (i / 2) % 15 % 5
+		}
+
+		if err := top.Add(v, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	items := top.Items()
+	if len(items) != 5 {
+		t.Fatalf("Expected 5 items, got %d", len(items))
+	}
+
+	for i, item := range items {
+		if item.Value < 0 || item.Value > 4 {
+			t.Errorf("Expected a heavy hitter, got %v", item)
+		}
+		if i > 0 && item.Count > items[i-1].Count {
+			t.Errorf("Expected items from the most to the least frequent, got %v", items)
+		}
+		if !top.Contains(item.Value) {
+			t.Errorf("Expected %d to be tracked", item.Value)
+		}
+	}
+
+	if count, _ := top.Count(0); count < 5000 {
+		t.Errorf("Expected count of 0 at least 5000, got %d", count)
+	}
+}
+
+func TestTopK_Replace(t *testing.T) {
+	top := sketch.NewTopK[string](2, 0.01, 0.01)
+
+	_ = top.Add("a", 3)
+	_ = top.Add("b", 1)
+	_ = top.Add("c", 2)
+
+	items := top.Items()
+	if len(items) != 2 || items[0] != (sketch.Item[string]{Value: "a", Count: 3}) || items[1] != (sketch.Item[string]{Value: "c", Count: 2}) {
+		t.Errorf("Expected [a:3 c:2], got %v", items)
+	}
+	if top.Contains("b") {
+		t.Errorf("Expected b to be replaced")
+	}
+
+	top.Clear()
+
+	if len(top.Items()) != 0 || top.Contains("a") {
+		t.Errorf("Expected no items after Clear, got %v", top.Items())
+	}
+}
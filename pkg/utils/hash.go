@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"encoding/json"
+	"hash/fnv"
+)
+
+// Encode returns the bytes a value is hashed from.
+// Uses json.Marshal, so that values of non-comparable types, such as structs, can be hashed.
+func Encode(value any) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Hash32 returns the 32-bit FNV-1a hash of the encoding of a value.
+func Hash32(value any) (uint32, error) {
+	b, err := Encode(value)
+	if err != nil {
+		return 0, err
+	}
+
+	h := fnv.New32a()
+	_, err = h.Write(b)
+
+	return h.Sum32(), err
+}
+
+// Hash64 returns the 64-bit FNV-1a hash of the encoding of a value.
+func Hash64(value any) (uint64, error) {
+	b, err := Encode(value)
+	if err != nil {
+		return 0, err
+	}
+
+	return Sum64(b), nil
+}
+
+// Sum64 returns the 64-bit FNV-1a hash of bytes, such as those returned by Encode.
+func Sum64(b []byte) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write(b)
+
+	return h.Sum64()
+}
+
+// DoubleHash returns the 64-bit hash h1 of a value, and a second hash h2 derived from it
+// by remixing, to derive any number of hashes as h1 + i*h2 (Kirsch and Mitzenmacher).
+// https://www.eecs.harvard.edu/~michaelm/postscripts/rsa2008.pdf
+//
+// h2 is a function of h1, not an independent hash: values colliding on h1 collide on h2.
+// It is forced odd, so that h1 + i*h2 cycles through all the residues of a power of two.
+func DoubleHash(value any) (h1, h2 uint64, err error) {
+	h1, err = Hash64(value)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return h1, Mix64(h1) | 1, nil
+}
+
+// Mix64 scrambles the bits of h with the SplitMix64 finalizer.
+func Mix64(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+// HashEntry returns a 64-bit hash of a key and a value, from the hashes of their encodings.
+//
+// Summing the hashes of entries gives a digest of a collection that does not depend on
+// the order of its entries, and can be updated as entries are added and removed.
+func HashEntry(key, value any) (uint64, error) {
+	k, err := Hash64(key)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := Hash64(value)
+	if err != nil {
+		return 0, err
+	}
+
+	return Mix64(k ^ Mix64(v)), nil
+}